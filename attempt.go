@@ -0,0 +1,91 @@
+package retry
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// Attempt is the metadata of an attempt.
+type Attempt struct {
+	// Number is the number of the attempt starting from 1.
+	Number int
+
+	// Delay is the delay slept before the attempt.
+	// It is zero for the first attempt.
+	Delay time.Duration
+
+	// Elapsed is the time elapsed since [Policy.Start] when the attempt starts.
+	Elapsed time.Duration
+
+	// Deadline is the time when retrying will be given up.
+	// It is the earlier of the deadline of the context and the end of [Policy.MaxElapsed].
+	// It is zero if there is no deadline.
+	Deadline time.Time
+}
+
+// Attempts returns an iterator over the attempts.
+// It is a range-over-func version of [Retrier.Continue].
+// The iteration stops when the retrier gives up, and [Retrier.Err] reports the reason.
+//
+//	retrier := policy.Start(ctx)
+//	for attempt := range retrier.Attempts() {
+//		log.Printf("attempt #%d", attempt.Number)
+//		if err := DoSomething(ctx); err == nil {
+//			return nil
+//		}
+//	}
+//	return retrier.Err()
+func (r *Retrier) Attempts() iter.Seq[Attempt] {
+	return func(yield func(Attempt) bool) {
+		for r.Continue() {
+			a := Attempt{
+				Number:   r.count,
+				Delay:    r.slept(),
+				Elapsed:  r.attempt.Sub(r.start),
+				Deadline: r.deadline(),
+			}
+			if !yield(a) {
+				return
+			}
+		}
+	}
+}
+
+// Attempts starts a new retrier and returns it with an iterator over its attempts.
+// It is a shorthand of [Policy.Start] and [Retrier.Attempts].
+// The retrier reports the reason after the iteration,
+// even if the iteration yields nothing, e.g. if the circuit of [Policy.Breaker] is open.
+//
+//	retrier, attempts := policy.Attempts(ctx)
+//	for attempt := range attempts {
+//		log.Printf("attempt #%d", attempt.Number)
+//		if err := DoSomething(ctx); err == nil {
+//			return nil
+//		}
+//	}
+//	return retrier.Err()
+func (p *Policy) Attempts(ctx context.Context) (*Retrier, iter.Seq[Attempt]) {
+	r := p.Start(ctx)
+	return r, r.Attempts()
+}
+
+// slept returns the delay slept before the current attempt.
+func (r *Retrier) slept() time.Duration {
+	if r.count <= 1 {
+		return 0
+	}
+	return max(r.sleep, 0)
+}
+
+// deadline returns the time when retrying will be given up.
+func (r *Retrier) deadline() time.Time {
+	deadline, _ := r.ctx.Deadline()
+	if limit := r.policy.MaxElapsed; limit > 0 {
+		end := r.start.Add(limit)
+		if deadline.IsZero() || end.Before(deadline) {
+			deadline = end
+		}
+	}
+	return deadline
+}
@@ -0,0 +1,161 @@
+//go:build go1.25
+// +build go1.25
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestRetrier_Attempts(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:   time.Second,
+			MaxDelay:   time.Minute,
+			MaxCount:   4,
+			MaxElapsed: time.Hour,
+		}
+		ctx, cancel := context.WithTimeout(t.Context(), time.Minute)
+		defer cancel()
+		deadline, _ := ctx.Deadline()
+
+		start := time.Now()
+		want := []Attempt{
+			{Number: 1, Delay: 0, Elapsed: 0, Deadline: deadline},
+			{Number: 2, Delay: time.Second, Elapsed: time.Second, Deadline: deadline},
+			{Number: 3, Delay: 2 * time.Second, Elapsed: 3 * time.Second, Deadline: deadline},
+			{Number: 4, Delay: 4 * time.Second, Elapsed: 7 * time.Second, Deadline: deadline},
+		}
+		var got []Attempt
+		retrier := policy.Start(ctx)
+		for a := range retrier.Attempts() {
+			got = append(got, a)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("want %d attempts, got %d", len(want), len(got))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("want %#v, got %#v", want[i], got[i])
+			}
+		}
+		if err := retrier.Err(); err != nil {
+			t.Errorf("want nil, got %v", err)
+		}
+		if d := time.Since(start); d != 7*time.Second {
+			t.Errorf("want 7s, got %s", d)
+		}
+	})
+}
+
+func TestRetrier_Attempts_Break(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:   time.Second,
+			MaxElapsed: time.Minute,
+		}
+		start := time.Now()
+		retrier := policy.Start(t.Context())
+		var last Attempt
+		for a := range retrier.Attempts() {
+			last = a
+			if a.Number == 3 {
+				break
+			}
+		}
+		if last.Number != 3 {
+			t.Errorf("want %d, got %d", 3, last.Number)
+		}
+		if want := start.Add(time.Minute); !last.Deadline.Equal(want) {
+			t.Errorf("want %s, got %s", want, last.Deadline)
+		}
+	})
+}
+
+func TestRetrier_Attempts_Cancel(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Second,
+		}
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		retrier := policy.Start(ctx)
+		count := 0
+		for range retrier.Attempts() {
+			count++
+			if count == 2 {
+				cancel()
+			}
+		}
+		if count != 2 {
+			t.Errorf("want %d, got %d", 2, count)
+		}
+		if err := retrier.Err(); err != context.Canceled {
+			t.Errorf("want %v, got %v", context.Canceled, err)
+		}
+	})
+}
+
+func TestPolicy_Attempts(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxDelay: time.Minute,
+		}
+
+		var numbers []int
+		start := time.Now()
+		retrier, attempts := policy.Attempts(context.Background())
+		for attempt := range attempts {
+			numbers = append(numbers, attempt.Number)
+			if attempt.Number == 3 {
+				break
+			}
+		}
+
+		want := []int{1, 2, 3}
+		if len(numbers) != len(want) {
+			t.Fatalf("want %v, got %v", want, numbers)
+		}
+		for i := range want {
+			if numbers[i] != want[i] {
+				t.Errorf("#%d: want %d, got %d", i, want[i], numbers[i])
+			}
+		}
+		if d := time.Since(start); d != 3*time.Second {
+			t.Errorf("want %s, got %s", 3*time.Second, d)
+		}
+		if got := retrier.Reason(); got != ReasonNone {
+			t.Errorf("want %s, got %s", ReasonNone, got)
+		}
+	})
+}
+
+func TestPolicy_Attempts_BreakerOpen(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := &CircuitBreaker{ConsecutiveFailures: 1}
+		b.Failure()
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxDelay: time.Minute,
+			Breaker:  b,
+		}
+
+		// the iteration yields nothing, but the retrier reports the reason.
+		retrier, attempts := policy.Attempts(context.Background())
+		for range attempts {
+			t.Error("want no attempts, but got one")
+		}
+		if err := retrier.Err(); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("want %v, got %v", ErrCircuitOpen, err)
+		}
+		if got := retrier.Reason(); got != ReasonCircuitOpen {
+			t.Errorf("want %s, got %s", ReasonCircuitOpen, got)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package retry
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	minDuration = time.Duration(math.MinInt64)
+	maxDuration = time.Duration(math.MaxInt64)
+)
+
+// Backoff is a strategy that computes delays between attempts.
+type Backoff interface {
+	// Next returns the delay before the attempt-th retry.
+	// attempt starts from 1, and prev is the delay returned by the previous call of Next.
+	// prev is zero for the first retry.
+	Next(attempt int, prev time.Duration) time.Duration
+}
+
+// BackoffFunc is an adapter to allow the use of ordinary functions as [Backoff].
+type BackoffFunc func(attempt int, prev time.Duration) time.Duration
+
+// Next implements [Backoff].
+func (f BackoffFunc) Next(attempt int, prev time.Duration) time.Duration {
+	return f(attempt, prev)
+}
+
+// ConstantBackoff returns a [Backoff] that always waits d.
+func ConstantBackoff(d time.Duration) Backoff {
+	return constantBackoff(d)
+}
+
+type constantBackoff time.Duration
+
+func (b constantBackoff) Next(attempt int, prev time.Duration) time.Duration {
+	return time.Duration(b)
+}
+
+// LinearBackoff returns a [Backoff] that waits initial, initial+step, initial+2*step, and so on.
+func LinearBackoff(initial, step time.Duration) Backoff {
+	return &linearBackoff{initial: initial, step: step}
+}
+
+type linearBackoff struct {
+	initial time.Duration
+	step    time.Duration
+}
+
+func (b *linearBackoff) Next(attempt int, prev time.Duration) time.Duration {
+	if attempt <= 1 {
+		return b.initial
+	}
+	n := time.Duration(attempt - 1)
+	if b.step > 0 && n > (maxDuration-b.initial)/b.step {
+		// overflow
+		return maxDuration
+	}
+	return b.initial + b.step*n
+}
+
+// FibonacciBackoff returns a [Backoff] that waits base multiplied by the Fibonacci numbers,
+// i.e. base, base, 2*base, 3*base, 5*base, and so on.
+func FibonacciBackoff(base time.Duration) Backoff {
+	return fibonacciBackoff(base)
+}
+
+type fibonacciBackoff time.Duration
+
+func (b fibonacciBackoff) Next(attempt int, prev time.Duration) time.Duration {
+	base := time.Duration(b)
+	if base <= 0 {
+		return base
+	}
+	x, y := base, base
+	for i := 1; i < attempt; i++ {
+		if y > maxDuration-x {
+			// overflow
+			return maxDuration
+		}
+		x, y = y, x+y
+	}
+	return x
+}
+
+// scaleDuration returns d * f.
+// The result saturates instead of overflowing.
+func scaleDuration(d time.Duration, f float64) time.Duration {
+	x := float64(d) * f
+	if x >= float64(maxDuration) {
+		return maxDuration
+	}
+	if x <= float64(minDuration) {
+		return minDuration
+	}
+	return time.Duration(x)
+}
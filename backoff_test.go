@@ -0,0 +1,101 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func testBackoff(t *testing.T, b Backoff, want []time.Duration) {
+	t.Helper()
+	var prev time.Duration
+	for i, w := range want {
+		got := b.Next(i+1, prev)
+		if got != w {
+			t.Errorf("attempt %d: want %s, got %s", i+1, w, got)
+		}
+		prev = got
+	}
+}
+
+func TestConstantBackoff(t *testing.T) {
+	testBackoff(t, ConstantBackoff(time.Second), []time.Duration{
+		time.Second, time.Second, time.Second, time.Second,
+	})
+}
+
+func TestLinearBackoff(t *testing.T) {
+	testBackoff(t, LinearBackoff(time.Second, 2*time.Second), []time.Duration{
+		time.Second, 3 * time.Second, 5 * time.Second, 7 * time.Second,
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		b := LinearBackoff(time.Second, time.Hour)
+		if got := b.Next(1<<40, 0); got != maxDuration {
+			t.Errorf("want %s, got %s", maxDuration, got)
+		}
+	})
+}
+
+func TestFibonacciBackoff(t *testing.T) {
+	testBackoff(t, FibonacciBackoff(time.Second), []time.Duration{
+		time.Second, time.Second, 2 * time.Second, 3 * time.Second, 5 * time.Second, 8 * time.Second,
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		b := FibonacciBackoff(time.Second)
+		if got := b.Next(1<<40, 0); got != maxDuration {
+			t.Errorf("want %s, got %s", maxDuration, got)
+		}
+	})
+}
+
+func TestBackoffFunc(t *testing.T) {
+	b := BackoffFunc(func(attempt int, prev time.Duration) time.Duration {
+		return prev + time.Duration(attempt)*time.Second
+	})
+	testBackoff(t, b, []time.Duration{
+		time.Second, 3 * time.Second, 6 * time.Second, 10 * time.Second,
+	})
+}
+
+func TestScaleDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		f    float64
+		want time.Duration
+	}{
+		{time.Second, 1.5, 1500 * time.Millisecond},
+		{time.Second, 3, 3 * time.Second},
+		{-time.Second, 2, -2 * time.Second},
+		{maxDuration / 2, 3, maxDuration},
+		{minDuration / 2, 3, minDuration},
+	}
+	for _, tt := range tests {
+		got := scaleDuration(tt.d, tt.f)
+		if got != tt.want {
+			t.Errorf("scaleDuration(%s, %g): want %s, got %s", tt.d, tt.f, tt.want, got)
+		}
+	}
+}
+
+func TestRetrier_NextDelayOverflow(t *testing.T) {
+	policies := []*Policy{
+		{MinDelay: time.Second, MaxDelay: maxDuration, Multiplier: 3},
+		{MinDelay: time.Second, MaxDelay: maxDuration, Exponent: 10},
+	}
+	for _, policy := range policies {
+		r := policy.Start(context.Background())
+		var prev time.Duration
+		for attempt := 1; attempt <= 1000; attempt++ {
+			d := r.nextDelay(attempt, prev)
+			if d < prev {
+				t.Fatalf("attempt %d: delay decreased from %s to %s", attempt, prev, d)
+			}
+			prev = d
+		}
+		if prev != maxDuration {
+			t.Errorf("want %s, got %s", maxDuration, prev)
+		}
+	}
+}
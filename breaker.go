@@ -0,0 +1,271 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned by [Retrier.Err] when the retrier gives up because of [Policy.Breaker].
+var ErrCircuitOpen = errors.New("retry: circuit breaker is open")
+
+// BreakerState is the state of a [CircuitBreaker].
+type BreakerState int
+
+const (
+	// BreakerClosed is the state that allows all attempts.
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen is the state that rejects all attempts.
+	BreakerOpen
+
+	// BreakerHalfOpen is the state that allows a limited number of probe attempts.
+	BreakerHalfOpen
+)
+
+// String implements fmt.Stringer.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreaker stops attempts while a dependency seems to be down.
+//
+// The circuit starts closed, and opens when the failures reach the thresholds.
+// While the circuit is open, the attempts fail fast with [ErrCircuitOpen].
+// After CoolDown, the circuit becomes half-open and allows HalfOpenProbes attempts.
+// If all of them succeed, the circuit is closed again; if any of them fails, the circuit opens again.
+//
+// [Policy.Do], [DoValue] and their variants report the results of the attempts automatically.
+// The temporary errors are counted as failures, and the time is read from [Policy.Clock].
+// The permanent errors and the errors after the context is done are not counted,
+// because they don't mean that the dependency is down.
+// When a policy with a circuit breaker is used by [Retrier.Continue],
+// call [CircuitBreaker.Success] or [CircuitBreaker.Failure] after each attempt.
+//
+// A CircuitBreaker is safe for concurrent use, and it can be shared by many policies.
+// It must not be copied after first use.
+type CircuitBreaker struct {
+	// ConsecutiveFailures is the number of consecutive failures that opens the circuit.
+	// If both ConsecutiveFailures and FailureRate are zero, 5 is used.
+	ConsecutiveFailures int
+
+	// FailureRate is the rate of failures in Window that opens the circuit.
+	// It is in the range (0, 1]. Zero disables it.
+	FailureRate float64
+
+	// MinAttempts is the minimum number of attempts in Window to evaluate FailureRate.
+	// Zero or negative value means 10.
+	MinAttempts int
+
+	// Window is the time window to evaluate FailureRate.
+	// Zero or negative value means 10 seconds.
+	Window time.Duration
+
+	// CoolDown is the duration that the circuit stays open.
+	// Zero or negative value means 5 seconds.
+	CoolDown time.Duration
+
+	// HalfOpenProbes is the number of probe attempts allowed in the half-open state.
+	// Zero or negative value means 1.
+	HalfOpenProbes int
+
+	// OnStateChange is called when the state changes.
+	OnStateChange func(from, to BreakerState)
+
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int       // consecutive failures in the closed state
+	window    *window   // successes and failures in the closed state
+	openedAt  time.Time // when the circuit opened
+	probes    int       // probe attempts in flight in the half-open state
+	successes int       // successful probe attempts in the half-open state
+}
+
+// State returns the current state.
+func (b *CircuitBreaker) State() BreakerState {
+	return b.stateAt(time.Now())
+}
+
+func (b *CircuitBreaker) stateAt(now time.Time) BreakerState {
+	b.mu.Lock()
+	from := b.state
+	b.refresh(now)
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return to
+}
+
+// Allow reports whether an attempt is allowed.
+// If it returns true, the result of the attempt must be reported by Success or Failure.
+func (b *CircuitBreaker) Allow() bool {
+	return b.allow(time.Now())
+}
+
+func (b *CircuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	from := b.state
+	b.refresh(now)
+	allowed := true
+	switch b.state {
+	case BreakerOpen:
+		allowed = false
+	case BreakerHalfOpen:
+		if b.probes >= max(b.HalfOpenProbes, 1) {
+			allowed = false
+		} else {
+			b.probes++
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+	return allowed
+}
+
+// Success reports that an attempt succeeded.
+func (b *CircuitBreaker) Success() {
+	b.success(time.Now())
+}
+
+func (b *CircuitBreaker) success(now time.Time) {
+	b.mu.Lock()
+	from := b.state
+	switch b.state {
+	case BreakerClosed:
+		b.failures = 0
+		b.getWindow().add(now, 0, 1)
+	case BreakerHalfOpen:
+		b.probes = max(b.probes-1, 0)
+		b.successes++
+		if b.successes >= max(b.HalfOpenProbes, 1) {
+			b.setState(BreakerClosed, now)
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// Failure reports that an attempt failed.
+func (b *CircuitBreaker) Failure() {
+	b.failure(time.Now())
+}
+
+func (b *CircuitBreaker) failure(now time.Time) {
+	b.mu.Lock()
+	from := b.state
+	switch b.state {
+	case BreakerClosed:
+		b.failures++
+		w := b.getWindow()
+		w.add(now, 1, 1)
+		if b.tripped(now) {
+			b.setState(BreakerOpen, now)
+		}
+	case BreakerHalfOpen:
+		b.setState(BreakerOpen, now)
+	}
+	to := b.state
+	b.mu.Unlock()
+
+	b.notify(from, to)
+}
+
+// ready reports whether the circuit will not be open at t, without taking a probe.
+func (b *CircuitBreaker) ready(t time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state != BreakerOpen || t.Sub(b.openedAt) >= b.coolDown()
+}
+
+// release returns an allowed attempt that didn't start, or whose result is not a failure of the dependency.
+func (b *CircuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerHalfOpen {
+		b.probes = max(b.probes-1, 0)
+	}
+}
+
+// tripped reports whether the failures reach the thresholds.
+func (b *CircuitBreaker) tripped(now time.Time) bool {
+	threshold := b.ConsecutiveFailures
+	if threshold <= 0 && b.FailureRate <= 0 {
+		threshold = 5
+	}
+	if threshold > 0 && b.failures >= threshold {
+		return true
+	}
+
+	if b.FailureRate > 0 {
+		successes, failures := b.window.sum(now)
+		minAttempts := b.MinAttempts
+		if minAttempts <= 0 {
+			minAttempts = 10
+		}
+		total := successes + failures
+		if total >= int64(minAttempts) && float64(failures)/float64(total) >= b.FailureRate {
+			return true
+		}
+	}
+	return false
+}
+
+// refresh makes the open circuit half-open after the cool down.
+func (b *CircuitBreaker) refresh(now time.Time) {
+	if b.state != BreakerOpen {
+		return
+	}
+	if now.Sub(b.openedAt) >= b.coolDown() {
+		b.setState(BreakerHalfOpen, now)
+	}
+}
+
+func (b *CircuitBreaker) coolDown() time.Duration {
+	if b.CoolDown <= 0 {
+		return 5 * time.Second
+	}
+	return b.CoolDown
+}
+
+func (b *CircuitBreaker) setState(state BreakerState, now time.Time) {
+	b.state = state
+	b.failures = 0
+	b.probes = 0
+	b.successes = 0
+	b.window = nil
+	if state == BreakerOpen {
+		b.openedAt = now
+	}
+}
+
+func (b *CircuitBreaker) getWindow() *window {
+	if b.window == nil {
+		size := b.Window
+		if size <= 0 {
+			size = 10 * time.Second
+		}
+		b.window = newWindow(size, 10)
+	}
+	return b.window
+}
+
+// notify calls OnStateChange if the state changed.
+// It must be called without holding b.mu.
+func (b *CircuitBreaker) notify(from, to BreakerState) {
+	if from != to && b.OnStateChange != nil {
+		b.OnStateChange(from, to)
+	}
+}
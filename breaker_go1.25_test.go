@@ -0,0 +1,349 @@
+//go:build go1.25
+// +build go1.25
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestCircuitBreaker_ConsecutiveFailures(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var changes []BreakerState
+		b := &CircuitBreaker{
+			ConsecutiveFailures: 3,
+			CoolDown:            time.Minute,
+			OnStateChange: func(from, to BreakerState) {
+				changes = append(changes, to)
+			},
+		}
+
+		// a success resets the consecutive failures.
+		b.Failure()
+		b.Failure()
+		b.Success()
+		b.Failure()
+		b.Failure()
+		if got := b.State(); got != BreakerClosed {
+			t.Fatalf("want %s, got %s", BreakerClosed, got)
+		}
+		b.Failure()
+		if got := b.State(); got != BreakerOpen {
+			t.Fatalf("want %s, got %s", BreakerOpen, got)
+		}
+		if b.Allow() {
+			t.Fatal("want to be denied, but allowed")
+		}
+
+		// the circuit becomes half-open after the cool down.
+		time.Sleep(time.Minute)
+		if !b.Allow() {
+			t.Fatal("want to be allowed, but not")
+		}
+		if b.Allow() {
+			t.Fatal("want only one probe, but allowed")
+		}
+
+		// the failed probe opens the circuit again.
+		b.Failure()
+		if got := b.State(); got != BreakerOpen {
+			t.Fatalf("want %s, got %s", BreakerOpen, got)
+		}
+
+		// the successful probe closes the circuit.
+		time.Sleep(time.Minute)
+		if !b.Allow() {
+			t.Fatal("want to be allowed, but not")
+		}
+		b.Success()
+		if got := b.State(); got != BreakerClosed {
+			t.Fatalf("want %s, got %s", BreakerClosed, got)
+		}
+
+		want := []BreakerState{BreakerOpen, BreakerHalfOpen, BreakerOpen, BreakerHalfOpen, BreakerClosed}
+		if len(changes) != len(want) {
+			t.Fatalf("want %v, got %v", want, changes)
+		}
+		for i := range want {
+			if changes[i] != want[i] {
+				t.Errorf("#%d: want %s, got %s", i, want[i], changes[i])
+			}
+		}
+	})
+}
+
+func TestCircuitBreaker_FailureRate(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := &CircuitBreaker{
+			FailureRate: 0.5,
+			MinAttempts: 4,
+			Window:      10 * time.Second,
+		}
+
+		// not enough attempts.
+		b.Failure()
+		b.Failure()
+		b.Success()
+		if got := b.State(); got != BreakerClosed {
+			t.Fatalf("want %s, got %s", BreakerClosed, got)
+		}
+
+		// the old attempts are expired.
+		time.Sleep(10 * time.Second)
+		b.Success()
+		b.Success()
+		b.Failure()
+		if got := b.State(); got != BreakerClosed {
+			t.Fatalf("want %s, got %s", BreakerClosed, got)
+		}
+		b.Failure()
+		if got := b.State(); got != BreakerOpen {
+			t.Fatalf("want %s, got %s", BreakerOpen, got)
+		}
+	})
+}
+
+func TestCircuitBreaker_HalfOpenProbes(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := &CircuitBreaker{
+			ConsecutiveFailures: 1,
+			HalfOpenProbes:      2,
+		}
+		b.Failure()
+		time.Sleep(5 * time.Second)
+
+		if !b.Allow() || !b.Allow() {
+			t.Fatal("want to be allowed, but not")
+		}
+		if b.Allow() {
+			t.Fatal("want to be denied, but allowed")
+		}
+		b.Success()
+		if got := b.State(); got != BreakerHalfOpen {
+			t.Fatalf("want %s, got %s", BreakerHalfOpen, got)
+		}
+		b.Success()
+		if got := b.State(); got != BreakerClosed {
+			t.Fatalf("want %s, got %s", BreakerClosed, got)
+		}
+	})
+}
+
+func TestCircuitBreaker_Concurrent(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := &CircuitBreaker{
+			ConsecutiveFailures: 1,
+			HalfOpenProbes:      3,
+		}
+		b.Failure()
+		time.Sleep(5 * time.Second)
+
+		var mu sync.Mutex
+		var allowed int
+		var wg sync.WaitGroup
+		for range 100 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if b.Allow() {
+					mu.Lock()
+					allowed++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if allowed != 3 {
+			t.Errorf("want %d, got %d", 3, allowed)
+		}
+	})
+}
+
+func TestDo_Breaker(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := &CircuitBreaker{
+			ConsecutiveFailures: 3,
+			CoolDown:            time.Minute,
+		}
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxCount: 10,
+			Breaker:  b,
+		}
+
+		// the circuit opens after 3 failures.
+		var count int
+		start := time.Now()
+		err := policy.Do(context.Background(), func() error {
+			count++
+			return io.ErrUnexpectedEOF
+		})
+		if !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("want %v to be wrapped in %v", ErrCircuitOpen, err)
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("want %v to be wrapped in %v", io.ErrUnexpectedEOF, err)
+		}
+		if count != 3 {
+			t.Errorf("want %d, got %d", 3, count)
+		}
+		if d := time.Since(start); d != 2*time.Second {
+			t.Errorf("want %s, got %s", 2*time.Second, d)
+		}
+
+		// fail fast while the circuit is open.
+		count = 0
+		start = time.Now()
+		err = policy.Do(context.Background(), func() error {
+			count++
+			return nil
+		})
+		if err != ErrCircuitOpen {
+			t.Errorf("want %v, got %v", ErrCircuitOpen, err)
+		}
+		if count != 0 {
+			t.Errorf("want %d, got %d", 0, count)
+		}
+		if d := time.Since(start); d != 0 {
+			t.Errorf("want %s, got %s", time.Duration(0), d)
+		}
+
+		// the probe closes the circuit.
+		time.Sleep(time.Minute)
+		err = policy.Do(context.Background(), func() error {
+			count++
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := b.State(); got != BreakerClosed {
+			t.Errorf("want %s, got %s", BreakerClosed, got)
+		}
+	})
+}
+
+func TestRetrier_BreakerCanceled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := &CircuitBreaker{
+			ConsecutiveFailures: 1,
+			HalfOpenProbes:      2,
+		}
+		b.Failure()
+		time.Sleep(5 * time.Second)
+
+		policy := &Policy{
+			MinDelay: time.Minute,
+			Breaker:  b,
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		retrier := policy.Start(ctx)
+		if !retrier.Continue() {
+			t.Fatal("want to continue, but not")
+		}
+		b.Success()
+
+		// the probe is allowed, but the deadline is exceeded while sleeping.
+		if retrier.Continue() {
+			t.Fatal("want not to continue, but do")
+		}
+
+		// the probe is returned to the breaker.
+		if !b.Allow() || !b.Allow() {
+			t.Error("want to be allowed, but not")
+		}
+	})
+}
+
+func TestDoContext_BreakerCanceled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := &CircuitBreaker{
+			ConsecutiveFailures: 3,
+		}
+		policy := &Policy{
+			MinDelay: time.Second,
+			Breaker:  b,
+		}
+
+		// the caller cancels the requests.
+		for range 3 {
+			ctx, cancel := context.WithCancel(context.Background())
+			policy.DoContext(ctx, func(ctx context.Context) error {
+				cancel()
+				return ctx.Err()
+			})
+		}
+		if got := b.State(); got != BreakerClosed {
+			t.Errorf("want %s, got %s", BreakerClosed, got)
+		}
+	})
+}
+
+func TestDo_BreakerPermanent(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := &CircuitBreaker{
+			ConsecutiveFailures: 3,
+		}
+		policy := &Policy{
+			MinDelay: time.Second,
+			Breaker:  b,
+		}
+
+		// the dependency works, but the requests are wrong.
+		for range 3 {
+			policy.Do(context.Background(), func() error {
+				return MarkPermanent(io.EOF)
+			})
+		}
+		if got := b.State(); got != BreakerClosed {
+			t.Errorf("want %s, got %s", BreakerClosed, got)
+		}
+	})
+}
+
+func TestRetrier_BreakerProbeAfterSleep(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := &CircuitBreaker{
+			ConsecutiveFailures: 1,
+			HalfOpenProbes:      2,
+		}
+		b.Failure()
+		time.Sleep(5 * time.Second)
+
+		policy := &Policy{
+			MinDelay: time.Minute,
+			Breaker:  b,
+		}
+		retrier := policy.Start(context.Background())
+		if !retrier.Continue() {
+			t.Fatal("want to continue, but not")
+		}
+		b.Success()
+
+		done := make(chan bool)
+		go func() {
+			done <- retrier.Continue()
+		}()
+		synctest.Wait()
+
+		// the sleeping retrier doesn't hold a probe.
+		if !b.Allow() || !b.Allow() {
+			t.Error("want to be allowed, but not")
+		}
+		b.Success()
+		b.Success()
+
+		// the circuit is closed while sleeping.
+		if !<-done {
+			t.Error("want to continue, but not")
+		}
+	})
+}
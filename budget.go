@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExhausted is returned by [Retrier.Err] when the retrier gives up because of [Policy.Budget].
+var ErrBudgetExhausted = errors.New("retry: retry budget exhausted")
+
+// budgetWindow is the time window of retry budgets.
+const budgetWindow = 10 * time.Second
+
+// Budget limits the number of retries shared by many retriers.
+// It prevents retries from amplifying an outage, when a policy is used by many concurrent requests.
+//
+// Within the last 10 seconds, the retries are allowed up to
+// ratio times the number of successful operations, plus minPerSecond per second.
+// It is safe for concurrent use.
+type Budget struct {
+	mu           sync.Mutex
+	ratio        float64
+	minPerSecond float64
+	window       *window
+}
+
+// NewBudget returns a new [Budget].
+// ratio is the ratio of retries to successful operations, e.g. 0.1 allows 1 retry per 10 successes.
+// minPerSecond is the number of retries allowed per second regardless of the successes.
+func NewBudget(ratio, minPerSecond float64) *Budget {
+	return &Budget{
+		ratio:        ratio,
+		minPerSecond: minPerSecond,
+		window:       newWindow(budgetWindow, 10),
+	}
+}
+
+// Deposit records a successful operation.
+// [Policy.Do], [DoValue] and their variants call it automatically.
+// Call it manually when a policy with a budget is used by [Retrier.Continue].
+func (b *Budget) Deposit() {
+	b.deposit(time.Now())
+}
+
+func (b *Budget) deposit(now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.window.add(now, 0, 1)
+}
+
+// Withdraw reports whether a retry is allowed, and records the retry if so.
+// [Retrier.Continue] calls it after sleeping for the next attempt,
+// and gives up without sleeping if no retry is available.
+func (b *Budget) Withdraw() bool {
+	return b.withdraw(time.Now())
+}
+
+func (b *Budget) withdraw(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.allows(now) {
+		return false
+	}
+	b.window.add(now, 1, 1)
+	return true
+}
+
+// available reports whether a retry is available without recording it.
+func (b *Budget) available(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.allows(now)
+}
+
+func (b *Budget) allows(now time.Time) bool {
+	successes, retries := b.window.sum(now)
+	limit := b.ratio*float64(successes) + b.minPerSecond*budgetWindow.Seconds()
+	return float64(retries) < limit
+}
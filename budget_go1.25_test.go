@@ -0,0 +1,132 @@
+//go:build go1.25
+// +build go1.25
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestBudget(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := NewBudget(0.5, 1)
+
+		// 1 retry per second is allowed in the window of 10 seconds.
+		for i := range 10 {
+			if !b.Withdraw() {
+				t.Fatalf("#%d: want to be allowed, but not", i)
+			}
+		}
+		if b.Withdraw() {
+			t.Fatal("want to be denied, but allowed")
+		}
+
+		// 4 successes allow 2 more retries.
+		for range 4 {
+			b.Deposit()
+		}
+		for i := range 2 {
+			if !b.Withdraw() {
+				t.Fatalf("#%d: want to be allowed, but not", i)
+			}
+		}
+		if b.Withdraw() {
+			t.Fatal("want to be denied, but allowed")
+		}
+
+		// the old retries are expired.
+		time.Sleep(10 * time.Second)
+		if !b.Withdraw() {
+			t.Fatal("want to be allowed, but not")
+		}
+	})
+}
+
+func TestBudget_Concurrent(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := NewBudget(0, 10)
+
+		var mu sync.Mutex
+		var allowed int
+		var wg sync.WaitGroup
+		for range 100 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 10 {
+					if b.Withdraw() {
+						mu.Lock()
+						allowed++
+						mu.Unlock()
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		if allowed != 100 {
+			t.Errorf("want %d, got %d", 100, allowed)
+		}
+	})
+}
+
+func TestDo_Budget(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Millisecond,
+			Budget:   NewBudget(0, 0.3),
+		}
+
+		count := 0
+		err := policy.Do(t.Context(), func() error {
+			count++
+			return io.ErrUnexpectedEOF
+		})
+		if !errors.Is(err, ErrBudgetExhausted) {
+			t.Errorf("want %v to be wrapped in %v", ErrBudgetExhausted, err)
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("want %v to be wrapped in %v", io.ErrUnexpectedEOF, err)
+		}
+
+		// the first attempt + 3 retries
+		if count != 4 {
+			t.Errorf("want %d, got %d", 4, count)
+		}
+	})
+}
+
+func TestDo_BudgetCanceled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		b := NewBudget(0, 0.1)
+		policy := &Policy{
+			MinDelay: time.Second,
+			Budget:   b,
+		}
+
+		// the context is canceled while sleeping.
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			time.Sleep(500 * time.Millisecond)
+			cancel()
+		}()
+		err := policy.Do(ctx, func() error {
+			return io.ErrUnexpectedEOF
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("want %v to be wrapped in %v", context.Canceled, err)
+		}
+
+		// the retry is not withdrawn.
+		if !b.Withdraw() {
+			t.Error("want to be allowed, but not")
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// Classifier decides whether an error is retryable.
+type Classifier func(err error) Decision
+
+// Decision is the result of a [Classifier].
+type Decision struct {
+	action action
+	delay  time.Duration
+}
+
+type action int
+
+const (
+	actionUndecided action = iota
+	actionRetry
+	actionStop
+	actionRetryAfter
+)
+
+var (
+	// Undecided means that the classifier has no opinion about the error.
+	Undecided = Decision{}
+
+	// Retry means that the error is retryable.
+	Retry = Decision{action: actionRetry}
+
+	// Stop means that the error is permanent, and retrying should be stopped.
+	Stop = Decision{action: actionStop}
+)
+
+// RetryAfter returns a [Decision] that the error is retryable after d.
+// d is used as the next delay instead of the back off, and it is capped at [Policy.MaxRetryAfter].
+func RetryAfter(d time.Duration) Decision {
+	return Decision{action: actionRetryAfter, delay: d}
+}
+
+// Delay returns the delay of the decision made by [RetryAfter].
+// It returns zero for other decisions.
+func (d Decision) Delay() time.Duration {
+	return d.delay
+}
+
+// String implements fmt.Stringer.
+func (d Decision) String() string {
+	switch d.action {
+	case actionUndecided:
+		return "Undecided"
+	case actionRetry:
+		return "Retry"
+	case actionStop:
+		return "Stop"
+	case actionRetryAfter:
+		return "RetryAfter(" + d.delay.String() + ")"
+	}
+	return "unknown"
+}
+
+// Any returns a [Classifier] that returns the first decision other than [Undecided] made by classifiers.
+func Any(classifiers ...Classifier) Classifier {
+	return func(err error) Decision {
+		for _, c := range classifiers {
+			if d := c(err); d != Undecided {
+				return d
+			}
+		}
+		return Undecided
+	}
+}
+
+// All returns a [Classifier] that returns a decision only if all classifiers agree with it.
+// Otherwise, it returns [Undecided].
+// If the classifiers return [RetryAfter], the longest delay is used.
+func All(classifiers ...Classifier) Classifier {
+	return func(err error) Decision {
+		var result Decision
+		for i, c := range classifiers {
+			d := c(err)
+			if d == Undecided {
+				return Undecided
+			}
+			if i == 0 {
+				result = d
+				continue
+			}
+			if d.action != result.action {
+				return Undecided
+			}
+			result.delay = max(result.delay, d.delay)
+		}
+		return result
+	}
+}
+
+// Not returns a [Classifier] that inverts the decision of c.
+// [Retry] and [RetryAfter] become [Stop], [Stop] becomes [Retry], and [Undecided] stays as it is.
+func Not(c Classifier) Classifier {
+	return func(err error) Decision {
+		switch c(err).action {
+		case actionRetry, actionRetryAfter:
+			return Stop
+		case actionStop:
+			return Retry
+		}
+		return Undecided
+	}
+}
+
+// ClassifyCanceled stops retrying if err is [context.Canceled].
+func ClassifyCanceled(err error) Decision {
+	if errors.Is(err, context.Canceled) {
+		return Stop
+	}
+	return Undecided
+}
+
+// ClassifyTimeout retries if err is a [net.Error] and it is a timeout.
+func ClassifyTimeout(err error) Decision {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return Retry
+	}
+	return Undecided
+}
+
+// ClassifyConnError retries if err is a connection error:
+// ECONNREFUSED, ECONNRESET or EPIPE.
+func ClassifyConnError(err error) Decision {
+	if errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return Retry
+	}
+	return Undecided
+}
+
+// ClassifyUnexpectedEOF retries if err is [io.ErrUnexpectedEOF].
+func ClassifyUnexpectedEOF(err error) Decision {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return Retry
+	}
+	return Undecided
+}
+
+// ClassifyNotExist stops retrying if err is [os.ErrNotExist].
+func ClassifyNotExist(err error) Decision {
+	if errors.Is(err, os.ErrNotExist) {
+		return Stop
+	}
+	return Undecided
+}
@@ -0,0 +1,115 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestClassifiers(t *testing.T) {
+	timeoutErr := &net.OpError{Op: "dial", Err: os.ErrDeadlineExceeded}
+	tests := []struct {
+		name       string
+		classifier Classifier
+		err        error
+		want       Decision
+	}{
+		{"canceled", ClassifyCanceled, fmt.Errorf("wrapped: %w", context.Canceled), Stop},
+		{"canceled/other", ClassifyCanceled, context.DeadlineExceeded, Undecided},
+		{"timeout", ClassifyTimeout, timeoutErr, Retry},
+		{"timeout/other", ClassifyTimeout, &net.OpError{Op: "dial", Err: errors.New("some error")}, Undecided},
+		{"conn/refused", ClassifyConnError, &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}, Retry},
+		{"conn/reset", ClassifyConnError, os.NewSyscallError("read", syscall.ECONNRESET), Retry},
+		{"conn/pipe", ClassifyConnError, os.NewSyscallError("write", syscall.EPIPE), Retry},
+		{"conn/other", ClassifyConnError, io.EOF, Undecided},
+		{"unexpected-eof", ClassifyUnexpectedEOF, io.ErrUnexpectedEOF, Retry},
+		{"not-exist", ClassifyNotExist, &os.PathError{Op: "open", Path: "foo", Err: os.ErrNotExist}, Stop},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.classifier(tt.err); got != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestClassifiers_Composition(t *testing.T) {
+	retry := func(error) Decision { return Retry }
+	stop := func(error) Decision { return Stop }
+	undecided := func(error) Decision { return Undecided }
+	after := func(d time.Duration) Classifier {
+		return func(error) Decision { return RetryAfter(d) }
+	}
+
+	tests := []struct {
+		name       string
+		classifier Classifier
+		want       Decision
+	}{
+		{"any/empty", Any(), Undecided},
+		{"any/first", Any(undecided, stop, retry), Stop},
+		{"any/undecided", Any(undecided, undecided), Undecided},
+		{"all/empty", All(), Undecided},
+		{"all/agree", All(retry, retry), Retry},
+		{"all/disagree", All(retry, stop), Undecided},
+		{"all/undecided", All(retry, undecided), Undecided},
+		{"all/retry-after", All(after(time.Second), after(2*time.Second)), RetryAfter(2 * time.Second)},
+		{"not/retry", Not(retry), Stop},
+		{"not/retry-after", Not(after(time.Second)), Stop},
+		{"not/stop", Not(stop), Retry},
+		{"not/undecided", Not(undecided), Undecided},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.classifier(errors.New("some error")); got != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDo_Classifier(t *testing.T) {
+	policy := &Policy{
+		MaxCount:   10,
+		Classifier: Any(ClassifyCanceled, ClassifyNotExist),
+	}
+
+	count := 0
+	err := policy.Do(context.Background(), func() error {
+		count++
+		if count < 3 {
+			return io.ErrUnexpectedEOF
+		}
+		return &os.PathError{Op: "open", Path: "foo", Err: os.ErrNotExist}
+	})
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("want %v, got %v", os.ErrNotExist, err)
+	}
+	if count != 3 {
+		t.Errorf("want %d, got %d", 3, count)
+	}
+}
+
+func TestDo_ClassifierWithMarker(t *testing.T) {
+	// the markers take precedence over the classifier.
+	policy := &Policy{
+		MaxCount:   3,
+		Classifier: func(error) Decision { return Stop },
+	}
+
+	count := 0
+	policy.Do(context.Background(), func() error {
+		count++
+		return MarkTemporary(errors.New("some error"))
+	})
+	if count != 3 {
+		t.Errorf("want %d, got %d", 3, count)
+	}
+}
@@ -0,0 +1,58 @@
+package retry
+
+import "time"
+
+// Clock is the source of the current time and timers.
+// It allows tests to control the time, e.g. by a fake clock.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// NewTimer creates a new Timer that sends the current time on its channel after at least duration d.
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is a timer created by [Clock].
+// It has the same semantics as [time.Timer].
+type Timer interface {
+	// C returns the channel on which the time is delivered.
+	C() <-chan time.Time
+
+	// Stop prevents the Timer from firing.
+	Stop() bool
+
+	// Reset changes the timer to expire after duration d.
+	Reset(d time.Duration) bool
+}
+
+// systemClock is the [Clock] of the system.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.t.C
+}
+
+func (t systemTimer) Stop() bool {
+	return t.t.Stop()
+}
+
+func (t systemTimer) Reset(d time.Duration) bool {
+	return t.t.Reset(d)
+}
+
+// since returns the time elapsed since t.
+func (r *Retrier) since(t time.Time) time.Duration {
+	return r.clock.Now().Sub(t)
+}
@@ -0,0 +1,69 @@
+package retry
+
+import "time"
+
+// DeadlineStrategy decides what the retrier does when the next delay would exceed the deadline of the context.
+type DeadlineStrategy int
+
+const (
+	// DeadlineGiveUp gives up retrying without sleeping.
+	// [Retrier.Err] returns [context.DeadlineExceeded], and [Retrier.Reason] returns [ReasonDeadlineWouldExceed].
+	DeadlineGiveUp DeadlineStrategy = iota
+
+	// DeadlineShortenDelay sleeps until [Policy.DeadlineMargin] before the deadline, and makes the last attempt.
+	// If the remaining time is shorter than the margin, the last attempt starts immediately.
+	DeadlineShortenDelay
+
+	// DeadlineTryImmediately makes the last attempt without sleeping.
+	DeadlineTryImmediately
+)
+
+// String implements fmt.Stringer.
+func (s DeadlineStrategy) String() string {
+	switch s {
+	case DeadlineGiveUp:
+		return "give-up"
+	case DeadlineShortenDelay:
+		return "shorten-delay"
+	case DeadlineTryImmediately:
+		return "try-immediately"
+	}
+	return "unknown"
+}
+
+// defaultDeadlineMargin is the default value of [Policy.DeadlineMargin].
+const defaultDeadlineMargin = 100 * time.Millisecond
+
+// fitDeadline shortens the sleep so that the last attempt starts before the deadline of the context.
+// The shortened sleep is marked as fitted, and sleepContext doesn't check the deadline again.
+func (r *Retrier) fitDeadline() {
+	r.fitted = false
+	strategy := r.policy.DeadlineStrategy
+	if strategy == DeadlineGiveUp || r.final {
+		return
+	}
+	deadline, ok := r.ctx.Deadline()
+	if !ok {
+		return
+	}
+	remaining := deadline.Sub(r.clock.Now())
+	if remaining >= r.sleep {
+		return
+	}
+
+	switch strategy {
+	case DeadlineShortenDelay:
+		r.sleep = max(remaining-r.deadlineMargin(), 0)
+	case DeadlineTryImmediately:
+		r.sleep = 0
+	}
+	r.final = true
+	r.fitted = true
+}
+
+func (r *Retrier) deadlineMargin() time.Duration {
+	if r.policy.DeadlineMargin <= 0 {
+		return defaultDeadlineMargin
+	}
+	return r.policy.DeadlineMargin
+}
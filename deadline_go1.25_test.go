@@ -0,0 +1,123 @@
+//go:build go1.25
+// +build go1.25
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func testDeadlineStrategy(t *testing.T, policy *Policy, want []time.Duration) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var got []time.Duration
+	start := time.Now()
+	retrier := policy.Start(ctx)
+	for retrier.Continue() {
+		got = append(got, time.Since(start))
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("#%d: want %s, got %s", i, want[i], got[i])
+		}
+	}
+	if reason := retrier.Reason(); reason != ReasonDeadlineWouldExceed {
+		t.Errorf("want %s, got %s", ReasonDeadlineWouldExceed, reason)
+	}
+	if !errors.Is(retrier.Err(), context.DeadlineExceeded) {
+		t.Errorf("want %v to be wrapped in %v", context.DeadlineExceeded, retrier.Err())
+	}
+}
+
+func TestDeadlineStrategy_GiveUp(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:         3 * time.Second,
+			MaxDelay:         time.Minute,
+			DeadlineStrategy: DeadlineGiveUp,
+		}
+
+		// the second delay (6s) would exceed the deadline (5s).
+		testDeadlineStrategy(t, policy, []time.Duration{0, 3 * time.Second})
+	})
+}
+
+func TestDeadlineStrategy_ShortenDelay(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:         3 * time.Second,
+			MaxDelay:         time.Minute,
+			DeadlineStrategy: DeadlineShortenDelay,
+			DeadlineMargin:   500 * time.Millisecond,
+		}
+
+		// the last attempt starts 500ms before the deadline.
+		testDeadlineStrategy(t, policy, []time.Duration{0, 3 * time.Second, 4500 * time.Millisecond})
+	})
+}
+
+func TestDeadlineStrategy_ShortenDelayWithoutMargin(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:         3 * time.Second,
+			MaxDelay:         time.Minute,
+			DeadlineStrategy: DeadlineShortenDelay,
+			DeadlineMargin:   time.Minute,
+		}
+
+		// the remaining time is shorter than the margin.
+		testDeadlineStrategy(t, policy, []time.Duration{0, 3 * time.Second, 3 * time.Second})
+	})
+}
+
+func TestDeadlineStrategy_TryImmediately(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:         3 * time.Second,
+			MaxDelay:         time.Minute,
+			DeadlineStrategy: DeadlineTryImmediately,
+		}
+
+		// the last attempt starts without sleeping.
+		testDeadlineStrategy(t, policy, []time.Duration{0, 3 * time.Second, 3 * time.Second})
+	})
+}
+
+func TestDo_DeadlineStrategy(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:         3 * time.Second,
+			MaxDelay:         time.Minute,
+			DeadlineStrategy: DeadlineShortenDelay,
+			DeadlineMargin:   time.Second,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		var count int
+		err := policy.Do(ctx, func() error {
+			count++
+			if count < 3 {
+				return errors.New("temporary error")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 3 {
+			t.Errorf("want %d, got %d", 3, count)
+		}
+	})
+}
@@ -0,0 +1,35 @@
+package retry
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDeadlineStrategy_ShortenDelayRealClock(t *testing.T) {
+	policy := &Policy{
+		MinDelay:         300 * time.Millisecond,
+		MaxDelay:         time.Minute,
+		DeadlineStrategy: DeadlineShortenDelay,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer cancel()
+
+	// the second delay (600ms) would exceed the deadline,
+	// so the last attempt starts within the default margin before the deadline.
+	count := 0
+	retrier := policy.Start(ctx)
+	for retrier.Continue() {
+		count++
+		if err := ctx.Err(); err != nil {
+			t.Errorf("#%d: the attempt starts after the deadline: %v", count, err)
+		}
+	}
+	if count != 3 {
+		t.Errorf("want %d, got %d", 3, count)
+	}
+	if reason := retrier.Reason(); reason != ReasonDeadlineWouldExceed {
+		t.Errorf("want %s, got %s", ReasonDeadlineWouldExceed, reason)
+	}
+}
@@ -0,0 +1,174 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// RetryError is the error returned by [Policy.Do] and [DoValue] when [Policy.AggregateErrors] is true.
+// It holds the errors of all attempts.
+type RetryError struct {
+	// Attempts is the list of the failed attempts.
+	Attempts []AttemptError
+
+	// Err is the reason why retrying was stopped other than the errors of the attempts.
+	// e.g. the error of the context or [ErrMaxElapsed].
+	// It is nil if the retry limit is reached or an attempt failed with a permanent error.
+	Err error
+
+	// Reason is the reason why retrying was stopped.
+	Reason Reason
+}
+
+// AttemptError is an error of an attempt.
+type AttemptError struct {
+	// Attempt is the number of the attempt starting from 1.
+	Attempt int
+
+	// Err is the error returned by the attempt.
+	Err error
+
+	// Start is the time when the attempt started.
+	Start time.Time
+
+	// Duration is the time the attempt took.
+	Duration time.Duration
+
+	// Delay is the delay before the attempt.
+	Delay time.Duration
+}
+
+// Error implements the error interface.
+func (e *RetryError) Error() string {
+	var buf strings.Builder
+	buf.WriteString("retry: ")
+	if e.Err != nil {
+		buf.WriteString(e.Err.Error())
+		buf.WriteString(": ")
+	}
+	fmt.Fprintf(&buf, "%d attempts failed", len(e.Attempts))
+	if last := e.Last(); last != nil {
+		buf.WriteString(", last error: ")
+		buf.WriteString(last.Error())
+	}
+	return buf.String()
+}
+
+// Last returns the error of the last attempt.
+// It returns nil if there is no attempt.
+func (e *RetryError) Last() error {
+	if len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1].Err
+}
+
+// Unwrap returns the errors of all attempts and Err.
+func (e *RetryError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Attempts)+2)
+	for _, a := range e.Attempts {
+		errs = append(errs, a.Err)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	if reason := e.Reason.sentinel(); reason != nil && reason != e.Err {
+		errs = append(errs, reason)
+	}
+	return errs
+}
+
+// addError records the error of the current attempt.
+func (r *Retrier) addError(err error) {
+	r.recordError(AttemptError{
+		Attempt: r.count,
+		Err:     err,
+		Start:   r.attempt,
+		Delay:   r.slept(),
+	})
+}
+
+// recordError records the error of an attempt.
+// e.Duration is filled by recordError.
+func (r *Retrier) recordError(e AttemptError) {
+	r.lastErr = e.Err
+	if !r.policy.AggregateErrors {
+		return
+	}
+
+	e.Duration = r.since(e.Start)
+	r.errs = append(r.errs, e)
+}
+
+// fail gives up because of the permanent error err returned by the operation.
+func (r *Retrier) fail(err error) error {
+	r.reason = ReasonPermanentError
+
+	// the backend accepted the request, but the request itself is wrong.
+	if t := r.policy.Throttler; t != nil {
+		t.accept(r.clock.Now())
+	}
+	return r.giveUp(err)
+}
+
+// giveUp returns the error that Do and DoValue return when they give up.
+// err is the last error returned by the operation.
+func (r *Retrier) giveUp(err error) error {
+	// if the retrier is still running, the operation failed with a permanent error.
+	r.notifyGiveUp(!r.done)
+	if r.policy.AggregateErrors {
+		return &RetryError{
+			Attempts: r.errs,
+			Err:      r.err,
+			Reason:   r.reason,
+		}
+	}
+	if r.err != nil {
+		reason := r.err
+		if r.policy.WrapReason && r.reason == ReasonDeadlineWouldExceed {
+			reason = ErrDeadlineWouldExceed
+		}
+		if err == nil {
+			return reason
+		}
+		e := &stopError{
+			reason: reason,
+			last:   err,
+		}
+		if cause := context.Cause(r.ctx); cause != nil && cause != r.err {
+			e.cause = cause
+		}
+		return e
+	}
+	if reason := r.reason.sentinel(); r.policy.WrapReason && reason != nil && err != nil && err != reason {
+		return &reasonError{
+			reason: reason,
+			err:    err,
+		}
+	}
+	return err
+}
+
+// stopError is the error returned when the retrier stops retrying, e.g. the context is canceled.
+// It wraps both the reason and the last error returned by the operation.
+type stopError struct {
+	reason error
+	cause  error
+	last   error
+}
+
+func (e *stopError) Error() string {
+	if e.cause != nil {
+		return fmt.Sprintf("%v (%v): last error: %v", e.reason, e.cause, e.last)
+	}
+	return fmt.Sprintf("%v: last error: %v", e.reason, e.last)
+}
+
+func (e *stopError) Unwrap() []error {
+	if e.cause != nil {
+		return []error{e.reason, e.cause, e.last}
+	}
+	return []error{e.reason, e.last}
+}
@@ -0,0 +1,91 @@
+//go:build go1.25
+// +build go1.25
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestDoValue_AggregateErrors(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:        time.Second,
+			MaxDelay:        time.Minute,
+			AggregateErrors: true,
+		}
+		ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+		defer cancel()
+
+		count := 0
+		_, err := DoValue(ctx, policy, func() (int, error) {
+			count++
+			time.Sleep(100 * time.Millisecond)
+			return 0, fmt.Errorf("error %d", count)
+		})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("want %v to be wrapped in %v", context.DeadlineExceeded, err)
+		}
+
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) {
+			t.Fatalf("want *RetryError, got %T", err)
+		}
+
+		// 0s, 1s and 2s; the next delay 4s exceeds the deadline.
+		wantDelays := []time.Duration{0, time.Second, 2 * time.Second}
+		if len(retryErr.Attempts) != len(wantDelays) {
+			t.Fatalf("want %d attempts, got %d", len(wantDelays), len(retryErr.Attempts))
+		}
+		for i, a := range retryErr.Attempts {
+			if a.Delay != wantDelays[i] {
+				t.Errorf("attempt %d: want delay %s, got %s", a.Attempt, wantDelays[i], a.Delay)
+			}
+			if a.Duration != 100*time.Millisecond {
+				t.Errorf("attempt %d: want duration 100ms, got %s", a.Attempt, a.Duration)
+			}
+			if want := fmt.Sprintf("error %d", i+1); a.Err.Error() != want {
+				t.Errorf("attempt %d: want %q, got %q", a.Attempt, want, a.Err.Error())
+			}
+		}
+		if retryErr.Err != context.DeadlineExceeded {
+			t.Errorf("want %v, got %v", context.DeadlineExceeded, retryErr.Err)
+		}
+	})
+}
+
+func TestDo_ContextCause(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Second,
+		}
+		errCause := errors.New("shutting down")
+		ctx, cancel := context.WithCancelCause(t.Context())
+		defer cancel(nil)
+
+		count := 0
+		err := policy.Do(ctx, func() error {
+			count++
+			if count == 2 {
+				cancel(errCause)
+			}
+			return io.ErrUnexpectedEOF
+		})
+		for _, target := range []error{context.Canceled, errCause, io.ErrUnexpectedEOF} {
+			if !errors.Is(err, target) {
+				t.Errorf("want %v to be wrapped in %v", target, err)
+			}
+		}
+
+		want := "context canceled (shutting down): last error: unexpected EOF"
+		if err.Error() != want {
+			t.Errorf("want %q, got %q", want, err.Error())
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestRetryError(t *testing.T) {
+	err := &RetryError{
+		Attempts: []AttemptError{
+			{Attempt: 1, Err: io.ErrUnexpectedEOF},
+			{Attempt: 2, Err: io.EOF},
+		},
+		Err: context.DeadlineExceeded,
+	}
+
+	want := "retry: context deadline exceeded: 2 attempts failed, last error: EOF"
+	if got := err.Error(); got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+	if got := err.Last(); got != io.EOF {
+		t.Errorf("want %v, got %v", io.EOF, got)
+	}
+	for _, target := range []error{io.ErrUnexpectedEOF, io.EOF, context.DeadlineExceeded} {
+		if !errors.Is(err, target) {
+			t.Errorf("want %v to be wrapped in %v", target, err)
+		}
+	}
+}
+
+func TestDo_AggregateErrors(t *testing.T) {
+	errs := []error{
+		errors.New("error 1"),
+		MarkTemporary(errors.New("error 2")),
+		errors.New("error 3"),
+	}
+	policy := &Policy{
+		MaxCount:        10,
+		AggregateErrors: true,
+	}
+	count := 0
+	err := policy.Do(context.Background(), func() error {
+		count++
+		if count == len(errs) {
+			return MarkPermanent(errs[count-1])
+		}
+		return errs[count-1]
+	})
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("want *RetryError, got %T", err)
+	}
+	if len(retryErr.Attempts) != len(errs) {
+		t.Fatalf("want %d attempts, got %d", len(errs), len(retryErr.Attempts))
+	}
+	for i, a := range retryErr.Attempts {
+		if a.Attempt != i+1 {
+			t.Errorf("want attempt %d, got %d", i+1, a.Attempt)
+		}
+		if !errors.Is(err, errs[i]) {
+			t.Errorf("want %v to be wrapped in %v", errs[i], err)
+		}
+	}
+	if retryErr.Err != nil {
+		t.Errorf("want nil, got %v", retryErr.Err)
+	}
+}
@@ -40,6 +40,43 @@ func ExamplePolicy_Start() {
 	// Success
 }
 
+func ExampleRetrier_Attempts() {
+	policy := &retry.Policy{
+		MaxCount: 3,
+	}
+
+	retrier := policy.Start(context.Background())
+	for attempt := range retrier.Attempts() {
+		fmt.Printf("#%d: unstable func is called!\n", attempt.Number)
+	}
+	if err := retrier.Err(); err != nil {
+		log.Fatal(err)
+	}
+
+	// Output:
+	// #1: unstable func is called!
+	// #2: unstable func is called!
+	// #3: unstable func is called!
+}
+
+func ExamplePolicy_Attempts() {
+	policy := &retry.Policy{
+		MaxCount: 3,
+	}
+
+	retrier, attempts := policy.Attempts(context.Background())
+	for attempt := range attempts {
+		fmt.Printf("#%d: unstable func is called!\n", attempt.Number)
+	}
+	fmt.Println(retrier.Reason())
+
+	// Output:
+	// #1: unstable func is called!
+	// #2: unstable func is called!
+	// #3: unstable func is called!
+	// max count exceeded
+}
+
 func ExamplePolicy_Do() {
 	policy := &retry.Policy{
 		MaxCount: 3,
@@ -0,0 +1,189 @@
+// Package fakeclock provides a fake clock for testing retry policies.
+// The time of the clock advances only when Advance or Set is called.
+package fakeclock
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shogo82148/go-retry/v2"
+)
+
+var _ retry.Clock = (*Clock)(nil)
+
+// Clock is a fake [retry.Clock] that is advanced manually.
+// It is safe for concurrent use.
+type Clock struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	now    time.Time
+	timers []*timer
+	auto   bool
+}
+
+// New returns a new fake clock that starts at now.
+func New(now time.Time) *Clock {
+	c := &Clock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now implements [retry.Clock].
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer implements [retry.Clock].
+func (c *Clock) NewTimer(d time.Duration) retry.Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &timer{
+		clock: c,
+		c:     make(chan time.Time, 1),
+	}
+	c.reset(t, d)
+	return t
+}
+
+// Advance advances the clock by d, and fires the timers that expire.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(c.now.Add(d))
+}
+
+// Set sets the clock to now, and fires the timers that expire.
+func (c *Clock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(now)
+}
+
+// AutoAdvance enables or disables auto advancing.
+// If it is enabled, the clock advances to the deadline of a timer as soon as the timer is started.
+// It is useful for running retry loops without waiting.
+func (c *Clock) AutoAdvance(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.auto = enabled
+}
+
+// Timers returns the number of active timers.
+func (c *Clock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// BlockUntil blocks until the clock has at least n active timers.
+// It is useful for waiting for a retrier to start sleeping.
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.timers) < n {
+		c.cond.Wait()
+	}
+}
+
+// AdvanceToNext advances the clock to the deadline of the earliest active timer, and fires it.
+// It returns the duration advanced, or zero if there is no active timer.
+func (c *Clock) AdvanceToNext() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.timers) == 0 {
+		return 0
+	}
+	next := c.timers[0].deadline
+	for _, t := range c.timers[1:] {
+		if t.deadline.Before(next) {
+			next = t.deadline
+		}
+	}
+	d := next.Sub(c.now)
+	c.set(next)
+	return d
+}
+
+func (c *Clock) set(now time.Time) {
+	c.now = now
+	timers := c.timers[:0]
+	for _, t := range c.timers {
+		if now.Before(t.deadline) {
+			timers = append(timers, t)
+			continue
+		}
+		t.active = false
+		select {
+		case t.c <- now:
+		default:
+		}
+	}
+	clear(c.timers[len(timers):])
+	c.timers = timers
+}
+
+// reset schedules t to expire after d.
+// c.mu must be held.
+func (c *Clock) reset(t *timer, d time.Duration) bool {
+	active := c.stop(t)
+	t.deadline = c.now.Add(d)
+	if d <= 0 {
+		t.c <- c.now
+		return active
+	}
+	t.active = true
+	c.timers = append(c.timers, t)
+	c.cond.Broadcast()
+	if c.auto {
+		c.set(t.deadline)
+	}
+	return active
+}
+
+// stop removes t from the active timers.
+// c.mu must be held.
+func (c *Clock) stop(t *timer) bool {
+	// drain the channel, as time.Timer does since Go 1.23.
+	select {
+	case <-t.c:
+	default:
+	}
+
+	if !t.active {
+		return false
+	}
+	t.active = false
+	for i, u := range c.timers {
+		if u == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+type timer struct {
+	clock    *Clock
+	c        chan time.Time
+	deadline time.Time
+	active   bool
+}
+
+func (t *timer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *timer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.stop(t)
+}
+
+func (t *timer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.reset(t, d)
+}
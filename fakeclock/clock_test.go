@@ -0,0 +1,165 @@
+package fakeclock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shogo82148/go-retry/v2"
+)
+
+func TestClock_Timer(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New(start)
+
+	timer := c.NewTimer(time.Second)
+	c.Advance(999 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Fatal("the timer fired too early")
+	default:
+	}
+
+	c.Advance(time.Millisecond)
+	select {
+	case now := <-timer.C():
+		if want := start.Add(time.Second); !now.Equal(want) {
+			t.Errorf("want %s, got %s", want, now)
+		}
+	default:
+		t.Fatal("the timer didn't fire")
+	}
+
+	if timer.Stop() {
+		t.Error("want false, the timer has already fired")
+	}
+	if timer.Reset(time.Second) {
+		t.Error("want false, the timer has already fired")
+	}
+	if !timer.Stop() {
+		t.Error("want true, the timer is active")
+	}
+	if n := c.Timers(); n != 0 {
+		t.Errorf("want no timers, got %d", n)
+	}
+}
+
+func TestClock_Retry(t *testing.T) {
+	c := New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	policy := &retry.Policy{
+		MinDelay: time.Second,
+		MaxDelay: time.Minute,
+		MaxCount: 5,
+		Clock:    c,
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- policy.Do(context.Background(), func() error {
+			return errors.New("some error")
+		})
+	}()
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}
+	for _, w := range want {
+		c.BlockUntil(1)
+		if d := c.AdvanceToNext(); d != w {
+			t.Errorf("want %s, got %s", w, d)
+		}
+	}
+
+	if err := <-done; err == nil {
+		t.Error("want error, got nil")
+	}
+}
+
+func TestClock_AutoAdvance(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New(start)
+	c.AutoAdvance(true)
+
+	policy := &retry.Policy{
+		MinDelay: time.Second,
+		MaxDelay: time.Minute,
+		MaxCount: 5,
+		Clock:    c,
+	}
+	retrier := policy.Start(context.Background())
+	for retrier.Continue() {
+	}
+
+	// 1s + 2s + 4s + 8s
+	if d := c.Now().Sub(start); d != 15*time.Second {
+		t.Errorf("want 15s, got %s", d)
+	}
+}
+
+func TestClock_Budget(t *testing.T) {
+	c := New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	c.AutoAdvance(true)
+
+	// 1 retry is allowed in the window of 10 seconds.
+	policy := &retry.Policy{
+		MinDelay: time.Second,
+		MaxCount: 10,
+		Budget:   retry.NewBudget(0, 0.1),
+		Clock:    c,
+	}
+	do := func() int {
+		var count int
+		policy.Do(context.Background(), func() error {
+			count++
+			return errors.New("some error")
+		})
+		return count
+	}
+
+	if got := do(); got != 2 {
+		t.Errorf("want %d, got %d", 2, got)
+	}
+	if got := do(); got != 1 {
+		t.Errorf("want %d, got %d", 1, got)
+	}
+
+	// the budget follows the fake clock.
+	c.Advance(10 * time.Second)
+	if got := do(); got != 2 {
+		t.Errorf("want %d, got %d", 2, got)
+	}
+}
+
+func TestClock_Breaker(t *testing.T) {
+	c := New(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	c.AutoAdvance(true)
+
+	b := &retry.CircuitBreaker{
+		ConsecutiveFailures: 2,
+		CoolDown:            time.Minute,
+	}
+	policy := &retry.Policy{
+		MinDelay: time.Second,
+		MaxCount: 10,
+		Breaker:  b,
+		Clock:    c,
+	}
+
+	err := policy.Do(context.Background(), func() error {
+		return errors.New("some error")
+	})
+	if !errors.Is(err, retry.ErrCircuitOpen) {
+		t.Errorf("want %v to be wrapped in %v", retry.ErrCircuitOpen, err)
+	}
+
+	// the cool down follows the fake clock.
+	c.Advance(time.Minute)
+	err = policy.Do(context.Background(), func() error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := b.State(); got != retry.BreakerClosed {
+		t.Errorf("want %s, got %s", retry.BreakerClosed, got)
+	}
+}
@@ -0,0 +1,45 @@
+package retry
+
+import "context"
+
+// DoValueWithFallback is like [DoValue], but it calls fallback with the final error
+// when the retries are exhausted, e.g. [Policy.MaxCount] is reached, [Policy.Budget] runs out
+// or the circuit of [Policy.Breaker] is open.
+// It returns the result of fallback in that case.
+//
+// fallback is not called if f fails with a permanent error, [Policy.Throttler] rejects the attempt, or ctx is done.
+func DoValueWithFallback[T any](ctx context.Context, policy *Policy, f func() (T, error), fallback func(err error) (T, error)) (T, error) {
+	retrier := policy.Start(ctx)
+	v, err := run(retrier, func(context.Context) (T, error) {
+		return f()
+	})
+	if err != nil && retrier.fallback() {
+		return fallback(err)
+	}
+	return v, err
+}
+
+// DoWithFallback is like [Policy.Do], but it calls fallback with the final error
+// when the retries are exhausted, e.g. [Policy.MaxCount] is reached, [Policy.Budget] runs out
+// or the circuit of [Policy.Breaker] is open.
+// It returns the result of fallback in that case.
+//
+// fallback is not called if f fails with a permanent error, [Policy.Throttler] rejects the attempt, or ctx is done.
+func (p *Policy) DoWithFallback(ctx context.Context, f func() error, fallback func(err error) error) error {
+	_, err := DoValueWithFallback(ctx, p, func() (struct{}, error) {
+		return struct{}{}, f()
+	}, func(err error) (struct{}, error) {
+		return struct{}{}, fallback(err)
+	})
+	return err
+}
+
+// fallback reports whether the retrier gave up because the retries are exhausted.
+// The rejection of the throttler is a permanent error, so it is not.
+func (r *Retrier) fallback() bool {
+	switch r.reason {
+	case ReasonPermanentError, ReasonThrottled:
+		return false
+	}
+	return r.ctx.Err() == nil
+}
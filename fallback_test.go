@@ -0,0 +1,138 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestDoValueWithFallback(t *testing.T) {
+	policy := &Policy{
+		MaxCount: 3,
+	}
+
+	var count int
+	var fallbackErr error
+	v, err := DoValueWithFallback(context.Background(), policy, func() (int, error) {
+		count++
+		return 0, io.ErrUnexpectedEOF
+	}, func(err error) (int, error) {
+		fallbackErr = err
+		return 42, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 42 {
+		t.Errorf("want %d, got %d", 42, v)
+	}
+	if count != 3 {
+		t.Errorf("want %d, got %d", 3, count)
+	}
+	if fallbackErr != io.ErrUnexpectedEOF {
+		t.Errorf("want %v, got %v", io.ErrUnexpectedEOF, fallbackErr)
+	}
+}
+
+func TestDoValueWithFallback_Budget(t *testing.T) {
+	policy := &Policy{
+		MaxCount: 3,
+		Budget:   NewBudget(0, 0),
+	}
+
+	var fallbackErr error
+	_, err := DoValueWithFallback(context.Background(), policy, func() (int, error) {
+		return 0, io.ErrUnexpectedEOF
+	}, func(err error) (int, error) {
+		fallbackErr = err
+		return 42, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(fallbackErr, ErrBudgetExhausted) {
+		t.Errorf("want %v to be wrapped in %v", ErrBudgetExhausted, fallbackErr)
+	}
+}
+
+func TestDoValueWithFallback_Permanent(t *testing.T) {
+	policy := &Policy{
+		MaxCount: 3,
+	}
+
+	_, err := DoValueWithFallback(context.Background(), policy, func() (int, error) {
+		return 0, MarkPermanent(io.EOF)
+	}, func(err error) (int, error) {
+		t.Error("want not to call fallback, but called")
+		return 42, nil
+	})
+	if err != io.EOF {
+		t.Errorf("want %v, got %v", io.EOF, err)
+	}
+}
+
+func TestDoValueWithFallback_Throttled(t *testing.T) {
+	th := NewThrottler(2)
+	th.rand = func() float64 { return 0 }
+	policy := &Policy{
+		MaxCount:  3,
+		Throttler: th,
+	}
+
+	// the retry is rejected, because the first attempt is not accepted.
+	_, err := DoValueWithFallback(context.Background(), policy, func() (int, error) {
+		return 0, io.ErrUnexpectedEOF
+	}, func(err error) (int, error) {
+		t.Error("want not to call fallback, but called")
+		return 42, nil
+	})
+	if !errors.Is(err, ErrThrottled) {
+		t.Errorf("want %v to be wrapped in %v", ErrThrottled, err)
+	}
+}
+
+func TestDoValueWithFallback_Canceled(t *testing.T) {
+	policy := &Policy{
+		MaxCount: 3,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := DoValueWithFallback(ctx, policy, func() (int, error) {
+		cancel()
+		return 0, io.ErrUnexpectedEOF
+	}, func(err error) (int, error) {
+		t.Error("want not to call fallback, but called")
+		return 42, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v to be wrapped in %v", context.Canceled, err)
+	}
+}
+
+func TestDoWithFallback(t *testing.T) {
+	policy := &Policy{
+		MaxCount: 3,
+	}
+
+	fallbackErr := errors.New("fallback error")
+	err := policy.DoWithFallback(context.Background(), func() error {
+		return io.ErrUnexpectedEOF
+	}, func(err error) error {
+		return fallbackErr
+	})
+	if err != fallbackErr {
+		t.Errorf("want %v, got %v", fallbackErr, err)
+	}
+
+	err = policy.DoWithFallback(context.Background(), func() error {
+		return nil
+	}, func(err error) error {
+		t.Error("want not to call fallback, but called")
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -12,44 +12,120 @@ import (
 // Error handling:
 //   - [MarkPermanent]: stops retrying immediately and returns the unwrapped error
 //   - [MarkTemporary]: continues retrying (explicit marker for retryable errors)
-//   - Unmarked errors: treated as temporary and retried
+//   - Unmarked errors: classified by [Policy.Classifier], or treated as temporary and retried
+//
+// If the error has the RetryAfter() time.Duration method, e.g. the error marked by [MarkTemporaryAfter],
+// the returned duration is used as the next delay.
+//
+// If ctx is done before f succeeds, the returned error wraps both the error of ctx and the last error returned by f.
+// If [Policy.WrapReason] is set, the returned error also wraps the sentinel error of the reason,
+// e.g. [ErrMaxCountExceeded] or [ErrPermanent], keeping the message of the last error.
 func DoValue[T any](ctx context.Context, policy *Policy, f func() (T, error)) (T, error) {
+	return doValue(ctx, policy, func(context.Context) (T, error) {
+		return f()
+	})
+}
+
+// DoValueContext is like [DoValue], but f receives a context for each attempt.
+// The context is derived from ctx, and it has a deadline if [Policy.AttemptTimeout] is positive.
+//
+// If an attempt fails because its own deadline is exceeded, DoValueContext retries it
+// even if the error is marked by [MarkPermanent].
+// On the other hand, the cancellation of ctx stops retrying.
+func DoValueContext[T any](ctx context.Context, policy *Policy, f func(ctx context.Context) (T, error)) (T, error) {
+	return doValue(ctx, policy, f)
+}
+
+func doValue[T any](ctx context.Context, policy *Policy, f func(ctx context.Context) (T, error)) (T, error) {
+	return run(policy.Start(ctx), f)
+}
+
+// run executes f with retrier.
+func run[T any](retrier *Retrier, f func(ctx context.Context) (T, error)) (T, error) {
 	var zero T
 	var err error
-	var target *temporary
 
-	retrier := policy.Start(ctx)
+	ctx, policy := retrier.ctx, retrier.policy
 	for retrier.Continue() {
 		var v T
-		v, err = f()
+		var expired bool
+		v, expired, err = attempt(ctx, policy, f)
 		if err == nil {
+			retrier.notifySuccess()
 			return v, nil
 		}
-
-		// short cut for calling Unwrap
-		if err, ok := err.(*myError); ok {
-			if err.tmp {
-				continue
-			}
-			return zero, err.error
+		retrier.addError(err)
+		retrier.setRetryAfter(err)
+		stop, perr := retrier.classify(err, expired)
+		retrier.reportFailure(stop)
+		if stop {
+			return zero, retrier.fail(perr)
 		}
+	}
+	return zero, retrier.giveUp(unmark(err))
+}
+
+// classify reports whether retrying should be stopped because of err.
+// If so, it returns the error to give up with.
+// expired reports whether the attempt exceeded its own deadline.
+func (r *Retrier) classify(err error, expired bool) (bool, error) {
+	if expired {
+		// the attempt timed out, but ctx is still alive.
+		return false, nil
+	}
 
-		if target == nil {
-			// lazy allocation of target
-			target = new(temporary)
+	// short cut for calling Unwrap
+	if err, ok := err.(*myError); ok {
+		if err.tmp {
+			return false, nil
 		}
-		if errors.As(err, target) {
-			if !(*target).temporary() {
-				return zero, err
-			}
+		return true, err.error
+	}
+
+	if r.tmp == nil {
+		// lazy allocation of target
+		r.tmp = new(temporary)
+	}
+	if errors.As(err, r.tmp) {
+		return !(*r.tmp).temporary(), err
+	}
+
+	if c := r.policy.Classifier; c != nil {
+		d := c(err)
+		switch d.action {
+		case actionStop:
+			return true, err
+		case actionRetryAfter:
+			r.setNextDelay(d.delay)
 		}
 	}
-	if err := retrier.err; err != nil {
-		return zero, err
+	return false, nil
+}
+
+// unmark unwraps the error if it's marked as temporary.
+func unmark(err error) error {
+	switch e := err.(type) {
+	case *myError:
+		return e.error
+	case *retryAfterError:
+		return e.error
 	}
-	if err, ok := err.(*myError); ok {
-		// Unwrap the error if it's marked as temporary.
-		return zero, err.error
+	return err
+}
+
+// attempt calls f once.
+// expired reports whether the attempt exceeded its own deadline while ctx is still alive.
+func attempt[T any](ctx context.Context, policy *Policy, f func(ctx context.Context) (T, error)) (v T, expired bool, err error) {
+	if policy.AttemptTimeout <= 0 {
+		v, err = f(ctx)
+		return
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, policy.AttemptTimeout)
+	defer cancel()
+	v, err = f(attemptCtx)
+	if err != nil {
+		expired = attemptCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil
 	}
-	return zero, err
+	return
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 )
@@ -87,9 +88,62 @@ func TestDoValue_Deadline(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 	defer cancel()
 	_, err := DoValue(ctx, policy, func() (int, error) {
-		return 0, errors.New("some error")
+		return 0, io.ErrUnexpectedEOF
 	})
-	if err != context.DeadlineExceeded {
-		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want %v to be wrapped in %v", context.DeadlineExceeded, err)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("want %v to be wrapped in %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func TestDoValueContext_AttemptTimeout(t *testing.T) {
+	policy := &Policy{
+		MaxCount:       10,
+		AttemptTimeout: 10 * time.Millisecond,
+	}
+	count := 0
+	v, err := DoValueContext(context.Background(), policy, func(ctx context.Context) (int, error) {
+		count++
+		if count < 3 {
+			<-ctx.Done()
+
+			// the attempt is retried even if it is marked as permanent.
+			return 0, MarkPermanent(ctx.Err())
+		}
+		return 42, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 42 {
+		t.Errorf("want %d, got %d", 42, v)
+	}
+	if count != 3 {
+		t.Errorf("want %d, got %d", 3, count)
+	}
+}
+
+func TestDoValueContext_Cancel(t *testing.T) {
+	policy := &Policy{
+		MaxCount:       10,
+		AttemptTimeout: time.Second,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	count := 0
+	_, err := DoValueContext(ctx, policy, func(ctx context.Context) (int, error) {
+		count++
+		cancel()
+		<-ctx.Done()
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want %v to be wrapped in %v", context.Canceled, err)
+	}
+	if count != 1 {
+		t.Errorf("want %d, got %d", 1, count)
 	}
 }
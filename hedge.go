@@ -0,0 +1,146 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// DoValueHedged is like [DoValueContext], but it doesn't wait for an attempt to fail before starting the next one.
+// If no attempt completes within the delay of the policy, DoValueHedged starts another attempt concurrently,
+// and returns the result of the first successful attempt.
+// The contexts of the other attempts are canceled.
+// The concurrent attempts are counted against [Policy.MaxCount].
+//
+// If an attempt fails with a temporary error, DoValueHedged waits for the other attempts and the next delay.
+// If an attempt fails with a permanent error, DoValueHedged cancels the other attempts and returns the error.
+// If [Policy.Throttler] rejects a new attempt, DoValueHedged waits for the running attempts without starting new ones.
+//
+// f must be safe for concurrent use.
+func DoValueHedged[T any](ctx context.Context, policy *Policy, f func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+
+	retrier := policy.Start(ctx)
+	if !retrier.Continue() {
+		return zero, retrier.giveUp(nil)
+	}
+
+	hedgeCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		v       T
+		expired bool
+		info    AttemptError
+	}
+	results := make(chan result)
+	running := 0
+	defer func() {
+		// the canceled attempts don't report their results.
+		if b := policy.Breaker; b != nil {
+			for range running {
+				b.release()
+			}
+		}
+	}()
+
+	launch := func() {
+		info := AttemptError{
+			Attempt: retrier.count,
+			Start:   retrier.attempt,
+			Delay:   retrier.slept(),
+		}
+		go func() {
+			res := result{info: info}
+			res.v, res.expired, res.info.Err = attempt(hedgeCtx, policy, f)
+			select {
+			case results <- res:
+			case <-hedgeCtx.Done():
+			}
+		}()
+		running++
+	}
+
+	var timer Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+
+	// schedule schedules the next attempt.
+	// It returns nil if no more attempts are allowed, and stopErr is the reason.
+	var stopErr error
+	schedule := func() <-chan time.Time {
+		retrier.sleep = retrier.nextSleep()
+
+		// check the limits as if the next attempt started.
+		retrier.count++
+		ok, err := retrier.check(retrier.sleep)
+		retrier.count--
+		if !ok {
+			stopErr = err
+			return nil
+		}
+
+		if timer == nil {
+			timer = retrier.clock.NewTimer(max(retrier.sleep, 0))
+		} else {
+			timer.Reset(max(retrier.sleep, 0))
+		}
+		return timer.C()
+	}
+
+	launch()
+	wait := schedule()
+	var err error
+	for running > 0 || wait != nil {
+		select {
+		case <-ctx.Done():
+			retrier.halt(ctx.Err())
+			return zero, retrier.giveUp(unmark(err))
+
+		case <-wait:
+			retrier.count++
+			if !retrier.throttle() {
+				// don't start a new attempt, but wait for the running attempts.
+				retrier.count--
+				stopErr = ErrThrottled
+				wait = nil
+				continue
+			}
+			if ok, err := retrier.acquire(); !ok {
+				retrier.count--
+				stopErr = err
+				wait = nil
+				continue
+			}
+
+			retrier.notifyRetry()
+			retrier.begin()
+			launch()
+			wait = schedule()
+
+		case res := <-results:
+			running--
+			if res.info.Err == nil {
+				retrier.notifySuccess()
+				return res.v, nil
+			}
+			err = res.info.Err
+			retrier.recordError(res.info)
+			retrier.setRetryAfter(err)
+			stop, perr := retrier.classify(err, res.expired)
+			retrier.reportFailure(stop)
+			if stop {
+				return zero, retrier.fail(perr)
+			}
+			if retrier.retryAfter > 0 && wait != nil {
+				// the delay is specified by the error.
+				timer.Stop()
+				wait = schedule()
+			}
+		}
+	}
+	retrier.halt(stopErr)
+	return zero, retrier.giveUp(unmark(err))
+}
@@ -0,0 +1,210 @@
+//go:build go1.25
+// +build go1.25
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestDoValueHedged(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxCount: 3,
+		}
+
+		var count atomic.Int32
+		var canceled atomic.Bool
+		start := time.Now()
+		v, err := DoValueHedged(context.Background(), policy, func(ctx context.Context) (int, error) {
+			n := count.Add(1)
+			if n == 1 {
+				// the first attempt is slow.
+				<-ctx.Done()
+				canceled.Store(true)
+				return 0, ctx.Err()
+			}
+			time.Sleep(500 * time.Millisecond)
+			return int(n), nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != 2 {
+			t.Errorf("want %d, got %d", 2, v)
+		}
+		if d := time.Since(start); d != 1500*time.Millisecond {
+			t.Errorf("want %s, got %s", 1500*time.Millisecond, d)
+		}
+
+		synctest.Wait()
+		if !canceled.Load() {
+			t.Error("want the first attempt to be canceled, but not")
+		}
+	})
+}
+
+func TestDoValueHedged_MaxCount(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxDelay: time.Minute,
+			MaxCount: 3,
+		}
+
+		var mu sync.Mutex
+		var starts []time.Duration
+		start := time.Now()
+		_, err := DoValueHedged(context.Background(), policy, func(ctx context.Context) (int, error) {
+			mu.Lock()
+			starts = append(starts, time.Since(start))
+			mu.Unlock()
+			time.Sleep(10 * time.Second)
+			return 0, io.ErrUnexpectedEOF
+		})
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("want %v, got %v", io.ErrUnexpectedEOF, err)
+		}
+
+		// the attempts start with the exponential back off.
+		want := []time.Duration{0, time.Second, 3 * time.Second}
+		if len(starts) != len(want) {
+			t.Fatalf("want %v, got %v", want, starts)
+		}
+		for i := range want {
+			if starts[i] != want[i] {
+				t.Errorf("#%d: want %s, got %s", i, want[i], starts[i])
+			}
+		}
+		if d := time.Since(start); d != 13*time.Second {
+			t.Errorf("want %s, got %s", 13*time.Second, d)
+		}
+	})
+}
+
+func TestDoValueHedged_Failure(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxDelay: time.Minute,
+			MaxCount: 3,
+		}
+
+		// the next attempt starts after the delay even if the previous one failed.
+		var count atomic.Int32
+		start := time.Now()
+		_, err := DoValueHedged(context.Background(), policy, func(ctx context.Context) (int, error) {
+			count.Add(1)
+			return 0, io.ErrUnexpectedEOF
+		})
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("want %v, got %v", io.ErrUnexpectedEOF, err)
+		}
+		if count.Load() != 3 {
+			t.Errorf("want %d, got %d", 3, count.Load())
+		}
+		if d := time.Since(start); d != 3*time.Second {
+			t.Errorf("want %s, got %s", 3*time.Second, d)
+		}
+	})
+}
+
+func TestDoValueHedged_Permanent(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxCount: 3,
+		}
+
+		permanentErr := errors.New("permanent error")
+		var count atomic.Int32
+		var canceled atomic.Bool
+		start := time.Now()
+		_, err := DoValueHedged(context.Background(), policy, func(ctx context.Context) (int, error) {
+			if count.Add(1) == 1 {
+				<-ctx.Done()
+				canceled.Store(true)
+				return 0, ctx.Err()
+			}
+			return 0, MarkPermanent(permanentErr)
+		})
+		if err != permanentErr {
+			t.Errorf("want %v, got %v", permanentErr, err)
+		}
+		if d := time.Since(start); d != time.Second {
+			t.Errorf("want %s, got %s", time.Second, d)
+		}
+
+		synctest.Wait()
+		if !canceled.Load() {
+			t.Error("want the first attempt to be canceled, but not")
+		}
+	})
+}
+
+func TestDoValueHedged_Deadline(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Second,
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		start := time.Now()
+		_, err := DoValueHedged(ctx, policy, func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, io.ErrUnexpectedEOF
+		})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("want %v to be wrapped in %v", context.DeadlineExceeded, err)
+		}
+		if d := time.Since(start); d != 5*time.Second {
+			t.Errorf("want %s, got %s", 5*time.Second, d)
+		}
+	})
+}
+
+func TestDoValueHedged_Throttled(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		th := NewThrottler(2)
+		th.rand = func() float64 { return 0 }
+		policy := &Policy{
+			MinDelay:  50 * time.Millisecond,
+			MaxCount:  3,
+			Throttler: th,
+		}
+
+		// the hedge is rejected, but the first attempt keeps running.
+		var count atomic.Int32
+		start := time.Now()
+		v, err := DoValueHedged(context.Background(), policy, func(ctx context.Context) (int, error) {
+			count.Add(1)
+			select {
+			case <-time.After(100 * time.Millisecond):
+				return 42, nil
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			}
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != 42 {
+			t.Errorf("want %d, got %d", 42, v)
+		}
+		if got := count.Load(); got != 1 {
+			t.Errorf("want %d, got %d", 1, got)
+		}
+		if d := time.Since(start); d != 100*time.Millisecond {
+			t.Errorf("want %s, got %s", 100*time.Millisecond, d)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package retry
+
+import (
+	"log/slog"
+	"time"
+)
+
+// Hooks is a set of functions called on retry events.
+// The functions must be safe for concurrent use if the policy is shared between goroutines.
+// A nil function is ignored.
+type Hooks struct {
+	// OnRetry is called before sleeping for the next attempt.
+	OnRetry func(Event)
+
+	// OnGiveUp is called when retrying is given up.
+	OnGiveUp func(Event)
+
+	// OnSuccess is called when the operation succeeds.
+	// It is called by [Policy.Do], [DoValue] and their variants, but not by [Retrier.Continue].
+	OnSuccess func(Event)
+}
+
+// Event describes a retry event.
+type Event struct {
+	// Attempt is the number of the attempts that have been made.
+	Attempt int
+
+	// Err is the last error returned by the operation.
+	// It is always nil if the retrier is used directly by [Retrier.Continue],
+	// because the retrier doesn't know the result of the operation.
+	Err error
+
+	// Delay is the delay before the next attempt.
+	// It is zero except for OnRetry.
+	Delay time.Duration
+
+	// Elapsed is the time elapsed since [Policy.Start] or the last reset.
+	Elapsed time.Duration
+}
+
+func (r *Retrier) event(delay time.Duration) Event {
+	return Event{
+		Attempt: r.count,
+		Err:     r.lastErr,
+		Delay:   delay,
+		Elapsed: r.since(r.start),
+	}
+}
+
+// notifyAttempt notifies that an attempt starts.
+func (r *Retrier) notifyAttempt() {
+	if m := r.policy.Metrics; m != nil {
+		m.Attempt(r.policy.Name)
+	}
+}
+
+// notifyRetry calls the OnRetry hook.
+func (r *Retrier) notifyRetry() {
+	if m := r.policy.Metrics; m != nil {
+		m.Retry(r.policy.Name, max(r.sleep, 0))
+	}
+
+	h, l := r.policy.Hooks, r.policy.Logger
+	if (h == nil || h.OnRetry == nil) && l == nil {
+		return
+	}
+
+	// the next attempt hasn't started yet.
+	e := r.event(max(r.sleep, 0))
+	e.Attempt--
+	if h != nil && h.OnRetry != nil {
+		h.OnRetry(e)
+	}
+	if l != nil {
+		r.log(r.policy.LogLevel, "retrying", e, slog.Duration("delay", e.Delay))
+	}
+}
+
+// notifyGiveUp calls the OnGiveUp hook.
+// It is called at most once.
+func (r *Retrier) notifyGiveUp(permanent bool) {
+	if r.done {
+		return
+	}
+	r.done = true
+	r.end = r.clock.Now()
+
+	if m := r.policy.Metrics; m != nil {
+		if permanent {
+			m.PermanentFailure(r.policy.Name)
+		} else {
+			m.Exhausted(r.policy.Name)
+		}
+	}
+
+	h, l := r.policy.Hooks, r.policy.Logger
+	if (h == nil || h.OnGiveUp == nil) && l == nil {
+		return
+	}
+
+	e := r.event(0)
+	if h != nil && h.OnGiveUp != nil {
+		h.OnGiveUp(e)
+	}
+	if l != nil {
+		r.log(slog.LevelWarn, "giving up", e)
+	}
+}
+
+// notifySuccess calls the OnSuccess hook.
+func (r *Retrier) notifySuccess() {
+	r.done = true
+	r.end = r.clock.Now()
+	r.reason = ReasonSucceeded
+	if b := r.policy.Budget; b != nil {
+		b.deposit(r.clock.Now())
+	}
+	if b := r.policy.Breaker; b != nil {
+		b.success(r.clock.Now())
+	}
+	if t := r.policy.Throttler; t != nil {
+		t.accept(r.clock.Now())
+	}
+	if m := r.policy.Metrics; m != nil {
+		m.Success(r.policy.Name)
+	}
+	if h := r.policy.Hooks; h != nil && h.OnSuccess != nil {
+		e := r.event(0)
+		e.Err = nil
+		h.OnSuccess(e)
+	}
+}
+
+// log logs the event e with the policy's logger.
+func (r *Retrier) log(level slog.Level, msg string, e Event, extra ...slog.Attr) {
+	l := r.policy.Logger
+	if !l.Enabled(r.ctx, level) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, 5)
+	if r.policy.Name != "" {
+		attrs = append(attrs, slog.String("policy", r.policy.Name))
+	}
+	attrs = append(attrs, slog.Int("attempt", e.Attempt))
+	attrs = append(attrs, extra...)
+	attrs = append(attrs, slog.Duration("elapsed", e.Elapsed))
+	if e.Err != nil {
+		attrs = append(attrs, slog.Any("error", e.Err))
+	}
+	l.LogAttrs(r.ctx, level, msg, attrs...)
+}
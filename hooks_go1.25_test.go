@@ -0,0 +1,158 @@
+//go:build go1.25
+// +build go1.25
+
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+type hookRecorder struct {
+	retries []Event
+	giveUps []Event
+	success []Event
+}
+
+func (r *hookRecorder) hooks() *Hooks {
+	return &Hooks{
+		OnRetry:   func(e Event) { r.retries = append(r.retries, e) },
+		OnGiveUp:  func(e Event) { r.giveUps = append(r.giveUps, e) },
+		OnSuccess: func(e Event) { r.success = append(r.success, e) },
+	}
+}
+
+func TestHooks_Success(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var rec hookRecorder
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxDelay: time.Minute,
+			Hooks:    rec.hooks(),
+		}
+
+		var errs []error
+		err := policy.Do(t.Context(), func() error {
+			if len(errs) < 2 {
+				err := fmt.Errorf("error %d", len(errs)+1)
+				errs = append(errs, err)
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []Event{
+			{Attempt: 1, Err: errs[0], Delay: time.Second, Elapsed: 0},
+			{Attempt: 2, Err: errs[1], Delay: 2 * time.Second, Elapsed: time.Second},
+		}
+		if len(rec.retries) != len(want) {
+			t.Fatalf("want %d retries, got %d", len(want), len(rec.retries))
+		}
+		for i, e := range rec.retries {
+			if e != want[i] {
+				t.Errorf("want %#v, got %#v", want[i], e)
+			}
+		}
+
+		if len(rec.giveUps) != 0 {
+			t.Errorf("want no give up events, got %d", len(rec.giveUps))
+		}
+		if len(rec.success) != 1 {
+			t.Fatalf("want 1 success event, got %d", len(rec.success))
+		}
+		if e := (Event{Attempt: 3, Elapsed: 3 * time.Second}); rec.success[0] != e {
+			t.Errorf("want %#v, got %#v", e, rec.success[0])
+		}
+	})
+}
+
+func TestHooks_GiveUp(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var rec hookRecorder
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxCount: 3,
+			Hooks:    rec.hooks(),
+		}
+
+		myErr := errors.New("some error")
+		err := policy.Do(t.Context(), func() error {
+			return myErr
+		})
+		if err != myErr {
+			t.Errorf("want %v, got %v", myErr, err)
+		}
+
+		if len(rec.retries) != 2 {
+			t.Errorf("want 2 retries, got %d", len(rec.retries))
+		}
+		if len(rec.success) != 0 {
+			t.Errorf("want no success events, got %d", len(rec.success))
+		}
+		if len(rec.giveUps) != 1 {
+			t.Fatalf("want 1 give up event, got %d", len(rec.giveUps))
+		}
+		if e := (Event{Attempt: 3, Err: myErr, Elapsed: 2 * time.Second}); rec.giveUps[0] != e {
+			t.Errorf("want %#v, got %#v", e, rec.giveUps[0])
+		}
+	})
+}
+
+func TestHooks_Permanent(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var rec hookRecorder
+		policy := &Policy{
+			MinDelay: time.Second,
+			Hooks:    rec.hooks(),
+		}
+
+		myErr := MarkPermanent(errors.New("some error"))
+		policy.Do(t.Context(), func() error {
+			return myErr
+		})
+
+		if len(rec.retries) != 0 {
+			t.Errorf("want no retries, got %d", len(rec.retries))
+		}
+		if len(rec.giveUps) != 1 {
+			t.Fatalf("want 1 give up event, got %d", len(rec.giveUps))
+		}
+		if e := (Event{Attempt: 1, Err: myErr}); rec.giveUps[0] != e {
+			t.Errorf("want %#v, got %#v", e, rec.giveUps[0])
+		}
+	})
+}
+
+func TestHooks_Continue(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var rec hookRecorder
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxCount: 2,
+			Hooks:    rec.hooks(),
+		}
+
+		retrier := policy.Start(t.Context())
+		for retrier.Continue() {
+		}
+
+		// calling Continue after giving up doesn't emit events.
+		retrier.Continue()
+
+		if len(rec.retries) != 1 {
+			t.Errorf("want 1 retry, got %d", len(rec.retries))
+		}
+		if len(rec.giveUps) != 1 {
+			t.Fatalf("want 1 give up event, got %d", len(rec.giveUps))
+		}
+		if e := (Event{Attempt: 2, Elapsed: time.Second}); rec.giveUps[0] != e {
+			t.Errorf("want %#v, got %#v", e, rec.giveUps[0])
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelDebug,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// remove non-deterministic attributes.
+			if a.Key == slog.TimeKey || a.Key == "elapsed" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	policy := &Policy{
+		Name:     "test",
+		MaxCount: 3,
+		Logger:   logger,
+		LogLevel: slog.LevelDebug,
+	}
+
+	err := policy.Do(context.Background(), func() error {
+		return errors.New("some error")
+	})
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+
+	want := []string{
+		`level=DEBUG msg=retrying policy=test attempt=1 delay=0s error="some error"`,
+		`level=DEBUG msg=retrying policy=test attempt=2 delay=0s error="some error"`,
+		`level=WARN msg="giving up" policy=test attempt=3 error="some error"`,
+	}
+	got := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("want %d lines, got %d:\n%s", len(want), len(got), buf.String())
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("want %q, got %q", want[i], got[i])
+		}
+	}
+}
+
+func TestLogger_Disabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		Level: slog.LevelError,
+	}))
+	policy := &Policy{
+		MaxCount: 3,
+		Logger:   logger,
+	}
+
+	policy.Do(context.Background(), func() error {
+		return errors.New("some error")
+	})
+	if buf.Len() != 0 {
+		t.Errorf("want no logs, got %q", buf.String())
+	}
+}
@@ -0,0 +1,234 @@
+// Package httpretry provides an [http.RoundTripper] that retries requests with a [retry.Policy].
+package httpretry
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/shogo82148/go-retry/v2"
+)
+
+// DefaultRetryStatusCodes is the list of the status codes that are retried by default.
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+var defaultPolicy = &retry.Policy{
+	MinDelay:      100 * time.Millisecond,
+	MaxDelay:      10 * time.Second,
+	MaxCount:      5,
+	MaxRetryAfter: 10 * time.Second,
+}
+
+// classifyError retries the connection errors, e.g. the connection is refused, reset or closed, or timed out.
+var classifyError = retry.Any(
+	retry.ClassifyConnError,
+	retry.ClassifyTimeout,
+	retry.ClassifyUnexpectedEOF,
+	func(err error) retry.Decision {
+		// the server closed the connection before responding.
+		if errors.Is(err, io.EOF) {
+			return retry.Retry
+		}
+		return retry.Undecided
+	},
+)
+
+// maxDrainBytes is the maximum number of bytes read from the discarded response body.
+// Reading the body enables reusing the connection.
+const maxDrainBytes = 4 << 10
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// Transport is an [http.RoundTripper] that retries requests.
+//
+// It retries a request if the underlying RoundTripper returns a connection error,
+// e.g. the connection is refused, reset or closed, or timed out,
+// or the status code of the response is in RetryStatusCodes.
+// The other errors, e.g. an unsupported protocol scheme or an invalid certificate, are not retried.
+// The Retry-After header of the response is used as the next delay, and it is capped at [retry.Policy.MaxRetryAfter].
+// If the retries are exhausted, the last response is returned.
+//
+// Only idempotent requests are retried by default.
+// The request that has a body is retried only if its GetBody field is set.
+type Transport struct {
+	// Base is the underlying RoundTripper.
+	// If Base is nil, [http.DefaultTransport] is used.
+	Base http.RoundTripper
+
+	// Policy is the retry policy.
+	// If Policy is nil, the default policy is used: it makes 5 attempts in total, i.e. 4 retries, with exponential back off from 100ms to 10s,
+	// and the Retry-After header is capped at 10s.
+	// Set [retry.Policy.MaxRetryAfter] of a custom policy, otherwise a long Retry-After blocks RoundTrip.
+	Policy *retry.Policy
+
+	// RetryStatusCodes is the list of the status codes to be retried.
+	// If RetryStatusCodes is nil, [DefaultRetryStatusCodes] is used.
+	RetryStatusCodes []int
+
+	// RetryNonIdempotent allows retrying non-idempotent requests, e.g. POST and PATCH.
+	RetryNonIdempotent bool
+}
+
+// RoundTrip implements [http.RoundTripper].
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.retryable(req) {
+		return t.base().RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	policy := t.policy()
+	var last *http.Response
+	var count int
+	resp, err := retry.DoValue(ctx, policy, func() (*http.Response, error) {
+		if last != nil {
+			// the last response is no longer needed.
+			discard(last)
+			last = nil
+		}
+
+		r := req
+		count++
+		if count > 1 && req.Body != nil && req.Body != http.NoBody {
+			// rewind the body.
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, retry.MarkPermanent(err)
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		resp, err := t.base().RoundTrip(r)
+		if err != nil {
+			if classifyError(err) == retry.Undecided {
+				return nil, retry.MarkPermanent(err)
+			}
+			return nil, err
+		}
+		if !slices.Contains(t.retryStatusCodes(), resp.StatusCode) {
+			return resp, nil
+		}
+		last = resp
+		return nil, &statusError{
+			code:       resp.StatusCode,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), now(policy)),
+		}
+	})
+	if err != nil && last != nil {
+		if ctx.Err() != nil {
+			discard(last)
+			return nil, err
+		}
+		// the retries are exhausted. return the last response as is.
+		return last, nil
+	}
+	return resp, err
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) policy() *retry.Policy {
+	if t.Policy != nil {
+		return t.Policy
+	}
+	return defaultPolicy
+}
+
+func (t *Transport) retryStatusCodes() []int {
+	if t.RetryStatusCodes != nil {
+		return t.RetryStatusCodes
+	}
+	return DefaultRetryStatusCodes
+}
+
+// retryable reports whether req can be retried.
+func (t *Transport) retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// the body can't be rewound.
+		return false
+	}
+	return t.RetryNonIdempotent || isIdempotent(req)
+}
+
+// isIdempotent reports whether req is idempotent.
+// It follows the rule of net/http.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	if _, ok := req.Header["Idempotency-Key"]; ok {
+		return true
+	}
+	if _, ok := req.Header["X-Idempotency-Key"]; ok {
+		return true
+	}
+	return false
+}
+
+// statusError is the error for the status codes to be retried.
+type statusError struct {
+	code       int
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("httpretry: unexpected status code: %d %s", e.code, http.StatusText(e.code))
+}
+
+// RetryAfter returns the delay specified by the Retry-After header.
+// It is used by the retry package.
+func (e *statusError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// parseRetryAfter parses the value of the Retry-After header.
+// The value is either a number of seconds or an HTTP-date.
+// It returns zero if the value is invalid.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if sec <= 0 {
+			return 0
+		}
+		if sec > int64(math.MaxInt64/time.Second) {
+			// overflow
+			return math.MaxInt64
+		}
+		return time.Duration(sec) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
+
+func now(policy *retry.Policy) time.Time {
+	if policy.Clock != nil {
+		return policy.Clock.Now()
+	}
+	return time.Now()
+}
+
+// discard drains and closes the body of resp.
+func discard(resp *http.Response) {
+	io.CopyN(io.Discard, resp.Body, maxDrainBytes)
+	resp.Body.Close()
+}
@@ -0,0 +1,57 @@
+//go:build go1.25
+// +build go1.25
+
+package httpretry
+
+import (
+	"net/http"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestTransport_DefaultMaxRetryAfter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var count int
+		tr := &Transport{
+			Base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				count++
+				resp := &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       http.NoBody,
+					Request:    req,
+				}
+				if count == 1 {
+					// the server asks to wait for a day.
+					resp.StatusCode = http.StatusServiceUnavailable
+					resp.Header.Set("Retry-After", "86400")
+				}
+				return resp, nil
+			}),
+		}
+
+		req, err := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Now()
+		resp, err := tr.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("want %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		if d := time.Since(start); d != 10*time.Second {
+			t.Errorf("want %s, got %s", 10*time.Second, d)
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package httpretry
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shogo82148/go-retry/v2"
+)
+
+var testPolicy = &retry.Policy{
+	MinDelay:      time.Millisecond,
+	MaxDelay:      10 * time.Millisecond,
+	MaxCount:      3,
+	MaxRetryAfter: 10 * time.Millisecond,
+}
+
+func TestTransport_RetryStatus(t *testing.T) {
+	var count atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if count.Add(1) < 3 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			io.WriteString(w, "unavailable")
+			return
+		}
+		io.WriteString(w, "ok")
+	}))
+	defer ts.Close()
+
+	client := &http.Client{
+		Transport: &Transport{Policy: testPolicy},
+	}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Errorf("unexpected response: %d %q", resp.StatusCode, body)
+	}
+	if got := count.Load(); got != 3 {
+		t.Errorf("want %d, got %d", 3, got)
+	}
+}
+
+func TestTransport_Exhausted(t *testing.T) {
+	var count atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+		io.WriteString(w, "bad gateway")
+	}))
+	defer ts.Close()
+
+	client := &http.Client{
+		Transport: &Transport{Policy: testPolicy},
+	}
+	resp, err := client.Get(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// the last response is returned.
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusBadGateway || string(body) != "bad gateway" {
+		t.Errorf("unexpected response: %d %q", resp.StatusCode, body)
+	}
+	if got := count.Load(); got != 3 {
+		t.Errorf("want %d, got %d", 3, got)
+	}
+}
+
+func TestTransport_NonIdempotent(t *testing.T) {
+	var count atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	client := &http.Client{
+		Transport: &Transport{Policy: testPolicy},
+	}
+	resp, err := client.Post(ts.URL, "text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("want %d, got %d", http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	if got := count.Load(); got != 1 {
+		t.Errorf("want %d, got %d", 1, got)
+	}
+}
+
+func TestTransport_RewindBody(t *testing.T) {
+	var count atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "hello" {
+			t.Errorf("want %q, got %q", "hello", body)
+		}
+		if count.Add(1) < 3 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer ts.Close()
+
+	client := &http.Client{
+		Transport: &Transport{
+			Policy:             testPolicy,
+			RetryNonIdempotent: true,
+		},
+	}
+	resp, err := client.Post(ts.URL, "text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("want %d, got %d", http.StatusCreated, resp.StatusCode)
+	}
+	if got := count.Load(); got != 3 {
+		t.Errorf("want %d, got %d", 3, got)
+	}
+}
+
+func TestTransport_ConnectionError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := ts.URL
+	ts.Close()
+
+	var count atomic.Int32
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		count.Add(1)
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	client := &http.Client{
+		Transport: &Transport{
+			Base:   base,
+			Policy: testPolicy,
+		},
+	}
+	_, err := client.Get(url)
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+	if got := count.Load(); got != 3 {
+		t.Errorf("want %d, got %d", 3, got)
+	}
+}
+
+func TestTransport_PermanentError(t *testing.T) {
+	var count atomic.Int32
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		count.Add(1)
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	client := &http.Client{
+		Transport: &Transport{
+			Base:   base,
+			Policy: testPolicy,
+		},
+	}
+
+	// unsupported protocol scheme is not retried.
+	_, err := client.Get("ftp://example.com/")
+	if err == nil {
+		t.Fatal("want error, got nil")
+	}
+	if got := count.Load(); got != 1 {
+		t.Errorf("want %d, got %d", 1, got)
+	}
+}
+
+func TestTransport_UnexpectedEOF(t *testing.T) {
+	var count atomic.Int32
+	base := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		count.Add(1)
+		return nil, io.ErrUnexpectedEOF
+	})
+	client := &http.Client{
+		Transport: &Transport{
+			Base:   base,
+			Policy: testPolicy,
+		},
+	}
+	_, err := client.Get("http://example.com/")
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("want %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+	if got := count.Load(); got != 3 {
+		t.Errorf("want %d, got %d", 3, got)
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-1", 0},
+		{"Mon, 01 Jan 2024 00:00:30 GMT", 30 * time.Second},
+		{"Sun, 31 Dec 2023 23:59:00 GMT", 0},
+		{"invalid", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.in, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q): want %s, got %s", tt.in, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// JitterMode is an algorithm for randomizing delays.
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/ for details.
+type JitterMode int
+
+const (
+	// JitterOffset adds a random duration in the range [0, Jitter) to the delay.
+	// If Jitter is negative, it subtracts a random duration in the range [0, -Jitter).
+	JitterOffset JitterMode = iota
+
+	// JitterFull chooses a random delay in the range [MinDelay, delay).
+	JitterFull
+
+	// JitterEqual keeps half of the delay and randomizes the other half.
+	// The delay is chosen in the range [max(MinDelay, delay/2), delay).
+	JitterEqual
+
+	// JitterDecorrelated chooses a random delay in the range [MinDelay, 3*previous delay),
+	// capped at MaxDelay. The exponential growth of the delay is ignored.
+	JitterDecorrelated
+)
+
+// String implements fmt.Stringer.
+func (m JitterMode) String() string {
+	switch m {
+	case JitterOffset:
+		return "offset"
+	case JitterFull:
+		return "full"
+	case JitterEqual:
+		return "equal"
+	case JitterDecorrelated:
+		return "decorrelated"
+	}
+	return "unknown"
+}
+
+// jitter returns the randomized delay for d.
+func (r *Retrier) jitter(d time.Duration) time.Duration {
+	if r.policy.JitterMode == JitterOffset {
+		return d + r.policy.randomJitter()
+	}
+	if d <= 0 {
+		return d
+	}
+
+	// the lower bound of the delay.
+	lo := min(max(r.policy.MinDelay, 0), d)
+
+	switch r.policy.JitterMode {
+	case JitterFull:
+		return lo + randN(d-lo)
+	case JitterEqual:
+		lo = max(lo, d/2)
+		return lo + randN(d-lo)
+	case JitterDecorrelated:
+		prev := r.sleep
+		if prev <= 0 {
+			prev = d
+		}
+		hi := maxDuration
+		if prev <= maxDuration/3 {
+			hi = prev * 3
+		}
+		if r.maxDelay > 0 {
+			hi = min(hi, r.maxDelay)
+		}
+		if hi < lo {
+			return lo
+		}
+		return lo + randN(hi-lo)
+	}
+	return d
+}
+
+// randN returns a random duration in the range [0, n).
+// It returns 0 if n <= 0.
+func randN(n time.Duration) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	return rand.N(n)
+}
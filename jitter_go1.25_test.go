@@ -0,0 +1,96 @@
+//go:build go1.25
+// +build go1.25
+
+package retry
+
+import (
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestRetry_JitterMode(t *testing.T) {
+	tests := []struct {
+		mode JitterMode
+
+		// lower returns the lower bound of the i-th delay.
+		lower func(base, prev time.Duration) time.Duration
+
+		// upper returns the upper bound of the i-th delay.
+		upper func(base, prev time.Duration) time.Duration
+	}{
+		{
+			mode:  JitterFull,
+			lower: func(base, prev time.Duration) time.Duration { return time.Second },
+			upper: func(base, prev time.Duration) time.Duration { return base },
+		},
+		{
+			mode:  JitterEqual,
+			lower: func(base, prev time.Duration) time.Duration { return max(time.Second, base/2) },
+			upper: func(base, prev time.Duration) time.Duration { return base },
+		},
+		{
+			mode:  JitterDecorrelated,
+			lower: func(base, prev time.Duration) time.Duration { return time.Second },
+			upper: func(base, prev time.Duration) time.Duration { return min(time.Minute, 3*prev) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				policy := &Policy{
+					MinDelay:   time.Second,
+					MaxDelay:   time.Minute,
+					JitterMode: tt.mode,
+				}
+				retrier := policy.Start(t.Context())
+
+				// sleepContext is not called as first
+				if !retrier.Continue() {
+					t.Fatal("want to continue, but not")
+				}
+
+				base, prev := time.Second, time.Second
+				for range 1000 {
+					start := time.Now()
+					if !retrier.Continue() {
+						t.Fatal("want to continue, but not")
+					}
+					delay := time.Since(start)
+					if delay < policy.MinDelay || delay > policy.MaxDelay {
+						t.Fatalf("want between %s and %s, got %s", policy.MinDelay, policy.MaxDelay, delay)
+					}
+					if lower := tt.lower(base, prev); delay < lower {
+						t.Errorf("want greater than or equal to %s, got %s", lower, delay)
+					}
+					if upper := tt.upper(base, prev); delay > upper {
+						t.Errorf("want less than or equal to %s, got %s", upper, delay)
+					}
+					base = min(base*2, policy.MaxDelay)
+					prev = delay
+				}
+			})
+		})
+	}
+}
+
+func TestRetry_JitterModeZeroDelay(t *testing.T) {
+	for _, mode := range []JitterMode{JitterFull, JitterEqual, JitterDecorrelated} {
+		t.Run(mode.String(), func(t *testing.T) {
+			synctest.Test(t, func(t *testing.T) {
+				policy := &Policy{
+					MaxCount:   10,
+					JitterMode: mode,
+				}
+				start := time.Now()
+				retrier := policy.Start(t.Context())
+				for retrier.Continue() {
+				}
+				if d := time.Since(start); d != 0 {
+					t.Errorf("want 0s, got %s", d)
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package retry
+
+import (
+	"expvar"
+	"sync"
+	"time"
+)
+
+// Metrics is a sink for the metrics of retrying.
+// The policy name is passed to each method, so a Metrics can be shared between policies.
+// The methods must be safe for concurrent use.
+type Metrics interface {
+	// Attempt is called when an attempt starts.
+	Attempt(policy string)
+
+	// Retry is called before sleeping for the next attempt.
+	Retry(policy string, delay time.Duration)
+
+	// Success is called when the operation succeeds.
+	// It is called by [Policy.Do], [DoValue] and their variants, but not by [Retrier.Continue].
+	Success(policy string)
+
+	// PermanentFailure is called when the operation fails with a permanent error.
+	// It is called by [Policy.Do], [DoValue] and their variants, but not by [Retrier.Continue].
+	PermanentFailure(policy string)
+
+	// Exhausted is called when the retrier gives up,
+	// e.g. the retry limit is reached, the time budget is exhausted or the context is done.
+	Exhausted(policy string)
+}
+
+var _ Metrics = (*ExpvarMetrics)(nil)
+
+// ExpvarMetrics is a [Metrics] that publishes the metrics via the expvar package.
+// The metrics are grouped by the policy name, and each group has the following counters:
+//
+//   - attempts: the number of attempts
+//   - retries: the number of retries
+//   - successes: the number of successful operations
+//   - permanent_failures: the number of operations failed with permanent errors
+//   - exhausted: the number of operations given up by the retrier
+//   - sleep_seconds: the total time of delays
+type ExpvarMetrics struct {
+	m        *expvar.Map
+	policies sync.Map // map[string]*expvar.Map
+}
+
+// NewExpvarMetrics returns a new [ExpvarMetrics] published as name.
+// As with [expvar.Publish], it panics if name is already registered.
+func NewExpvarMetrics(name string) *ExpvarMetrics {
+	return &ExpvarMetrics{
+		m: expvar.NewMap(name),
+	}
+}
+
+func (m *ExpvarMetrics) policy(name string) *expvar.Map {
+	if v, ok := m.policies.Load(name); ok {
+		return v.(*expvar.Map)
+	}
+	v, loaded := m.policies.LoadOrStore(name, new(expvar.Map))
+	if !loaded {
+		m.m.Set(name, v.(*expvar.Map))
+	}
+	return v.(*expvar.Map)
+}
+
+// Attempt implements [Metrics].
+func (m *ExpvarMetrics) Attempt(policy string) {
+	m.policy(policy).Add("attempts", 1)
+}
+
+// Retry implements [Metrics].
+func (m *ExpvarMetrics) Retry(policy string, delay time.Duration) {
+	p := m.policy(policy)
+	p.Add("retries", 1)
+	p.AddFloat("sleep_seconds", delay.Seconds())
+}
+
+// Success implements [Metrics].
+func (m *ExpvarMetrics) Success(policy string) {
+	m.policy(policy).Add("successes", 1)
+}
+
+// PermanentFailure implements [Metrics].
+func (m *ExpvarMetrics) PermanentFailure(policy string) {
+	m.policy(policy).Add("permanent_failures", 1)
+}
+
+// Exhausted implements [Metrics].
+func (m *ExpvarMetrics) Exhausted(policy string) {
+	m.policy(policy).Add("exhausted", 1)
+}
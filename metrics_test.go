@@ -0,0 +1,74 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+// expvarRuns makes the published names unique, because expvar names can't be reused in a process.
+var expvarRuns atomic.Int64
+
+func TestExpvarMetrics(t *testing.T) {
+	name := fmt.Sprintf("go-retry-test-%d", expvarRuns.Add(1))
+	metrics := NewExpvarMetrics(name)
+	policy := &Policy{
+		Name:     "payment",
+		MaxCount: 3,
+		Metrics:  metrics,
+	}
+
+	// success after 2 retries
+	count := 0
+	if err := policy.Do(context.Background(), func() error {
+		count++
+		if count < 3 {
+			return errors.New("some error")
+		}
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// permanent failure
+	policy.Do(context.Background(), func() error {
+		return MarkPermanent(errors.New("permanent error"))
+	})
+
+	// exhausted
+	policy.Do(context.Background(), func() error {
+		return errors.New("some error")
+	})
+
+	want := map[string]int64{
+		"attempts":           7,
+		"retries":            4,
+		"successes":          1,
+		"permanent_failures": 1,
+		"exhausted":          1,
+	}
+	m, ok := expvar.Get(name).(*expvar.Map)
+	if !ok {
+		t.Fatalf("%s is not published", name)
+	}
+	p, ok := m.Get("payment").(*expvar.Map)
+	if !ok {
+		t.Fatal("payment is not published")
+	}
+	for key, v := range want {
+		got, ok := p.Get(key).(*expvar.Int)
+		if !ok {
+			t.Errorf("%s is not published", key)
+			continue
+		}
+		if got.Value() != v {
+			t.Errorf("%s: want %d, got %d", key, v, got.Value())
+		}
+	}
+	if got, ok := p.Get("sleep_seconds").(*expvar.Float); !ok || got.Value() != 0 {
+		t.Errorf("sleep_seconds: want 0, got %v", p.Get("sleep_seconds"))
+	}
+}
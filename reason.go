@@ -0,0 +1,154 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrMaxCountExceeded is wrapped in the error returned by [Policy.Do] and [DoValue]
+	// when [Policy.MaxCount] is exceeded, if [Policy.WrapReason] is set.
+	ErrMaxCountExceeded = errors.New("retry: max retry count exceeded")
+
+	// ErrPermanent is wrapped in the error returned by [Policy.Do] and [DoValue]
+	// when the operation fails with a permanent error, if [Policy.WrapReason] is set.
+	ErrPermanent = errors.New("retry: permanent error")
+
+	// ErrDeadlineWouldExceed is wrapped in the error returned by [Policy.Do] and [DoValue]
+	// when the next delay would exceed the deadline of the context, if [Policy.WrapReason] is set.
+	// It wraps [context.DeadlineExceeded].
+	// [Retrier.Err] returns [context.DeadlineExceeded] as is, and [Retrier.Reason] reports [ReasonDeadlineWouldExceed].
+	ErrDeadlineWouldExceed = fmt.Errorf("retry: deadline would be exceeded: %w", context.DeadlineExceeded)
+)
+
+// Reason is the reason why a [Retrier] stopped retrying.
+type Reason int
+
+const (
+	// ReasonNone means that the retrier is still running, or the caller exited the loop of [Retrier.Continue].
+	ReasonNone Reason = iota
+
+	// ReasonSucceeded means that the operation succeeded.
+	ReasonSucceeded
+
+	// ReasonPermanentError means that the operation failed with a permanent error.
+	ReasonPermanentError
+
+	// ReasonMaxCountExceeded means that [Policy.MaxCount] is exceeded.
+	ReasonMaxCountExceeded
+
+	// ReasonContextDone means that the context is done.
+	ReasonContextDone
+
+	// ReasonDeadlineWouldExceed means that the next delay would exceed the deadline of the context.
+	ReasonDeadlineWouldExceed
+
+	// ReasonBudgetExhausted means that [Policy.Budget] is exhausted.
+	ReasonBudgetExhausted
+
+	// ReasonMaxElapsed means that [Policy.MaxElapsed] would be exceeded.
+	ReasonMaxElapsed
+
+	// ReasonCircuitOpen means that the circuit of [Policy.Breaker] is open.
+	ReasonCircuitOpen
+
+	// ReasonThrottled means that [Policy.Throttler] rejected the attempt.
+	ReasonThrottled
+)
+
+// String implements fmt.Stringer.
+func (r Reason) String() string {
+	switch r {
+	case ReasonNone:
+		return "none"
+	case ReasonSucceeded:
+		return "succeeded"
+	case ReasonPermanentError:
+		return "permanent error"
+	case ReasonMaxCountExceeded:
+		return "max count exceeded"
+	case ReasonContextDone:
+		return "context done"
+	case ReasonDeadlineWouldExceed:
+		return "deadline would exceed"
+	case ReasonBudgetExhausted:
+		return "budget exhausted"
+	case ReasonMaxElapsed:
+		return "max elapsed"
+	case ReasonCircuitOpen:
+		return "circuit open"
+	case ReasonThrottled:
+		return "throttled"
+	}
+	return "unknown"
+}
+
+// sentinel returns the error that represents the reason.
+// It returns nil if the reason has no sentinel error.
+func (r Reason) sentinel() error {
+	switch r {
+	case ReasonPermanentError:
+		return ErrPermanent
+	case ReasonMaxCountExceeded:
+		return ErrMaxCountExceeded
+	case ReasonDeadlineWouldExceed:
+		return ErrDeadlineWouldExceed
+	case ReasonBudgetExhausted:
+		return ErrBudgetExhausted
+	case ReasonMaxElapsed:
+		return ErrMaxElapsed
+	case ReasonCircuitOpen:
+		return ErrCircuitOpen
+	case ReasonThrottled:
+		return ErrThrottled
+	}
+	return nil
+}
+
+// Reason returns the reason why the retrier stopped retrying.
+//
+// [Retrier.Continue] sets the reason when it returns false.
+// [Policy.Do], [DoValue] and their variants also set [ReasonSucceeded] and [ReasonPermanentError],
+// which can't be known by Continue.
+func (r *Retrier) Reason() Reason {
+	return r.reason
+}
+
+// reasonOf returns the reason for the error that stops retrying.
+// err is nil if the retry limit is reached.
+func (r *Retrier) reasonOf(err error) Reason {
+	switch err {
+	case nil:
+		return ReasonMaxCountExceeded
+	case ErrMaxElapsed:
+		return ReasonMaxElapsed
+	case ErrBudgetExhausted:
+		return ReasonBudgetExhausted
+	case ErrCircuitOpen:
+		return ReasonCircuitOpen
+	case ErrThrottled:
+		return ReasonThrottled
+	}
+	if r.ctx.Err() != nil {
+		return ReasonContextDone
+	}
+
+	// sleepContext gave up without sleeping.
+	return ReasonDeadlineWouldExceed
+}
+
+// reasonError is the error that wraps the last error returned by the operation with the sentinel error of the reason.
+// It has the same message as the last error.
+type reasonError struct {
+	reason error
+	err    error
+}
+
+func (e *reasonError) Error() string {
+	return e.err.Error()
+}
+
+func (e *reasonError) Unwrap() []error {
+	return []error{e.err, e.reason}
+}
@@ -0,0 +1,146 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestRetrier_Reason(t *testing.T) {
+	t.Run("max count exceeded", func(t *testing.T) {
+		policy := &Policy{MaxCount: 3}
+		retrier := policy.Start(context.Background())
+		for retrier.Continue() {
+			if got := retrier.Reason(); got != ReasonNone {
+				t.Errorf("want %s, got %s", ReasonNone, got)
+			}
+		}
+		if got := retrier.Reason(); got != ReasonMaxCountExceeded {
+			t.Errorf("want %s, got %s", ReasonMaxCountExceeded, got)
+		}
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		policy := &Policy{}
+		retrier := policy.Start(ctx)
+		for retrier.Continue() {
+			cancel()
+		}
+		if got := retrier.Reason(); got != ReasonContextDone {
+			t.Errorf("want %s, got %s", ReasonContextDone, got)
+		}
+	})
+
+	t.Run("deadline would exceed", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		policy := &Policy{MinDelay: 2 * time.Hour}
+		retrier := policy.Start(ctx)
+		for retrier.Continue() {
+		}
+		if got := retrier.Reason(); got != ReasonDeadlineWouldExceed {
+			t.Errorf("want %s, got %s", ReasonDeadlineWouldExceed, got)
+		}
+		// Err is compatible with the error before Reason is introduced.
+		if err := retrier.Err(); err != context.DeadlineExceeded {
+			t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+		}
+	})
+
+	t.Run("budget exhausted", func(t *testing.T) {
+		policy := &Policy{Budget: NewBudget(0, 0)}
+		retrier := policy.Start(context.Background())
+		for retrier.Continue() {
+		}
+		if got := retrier.Reason(); got != ReasonBudgetExhausted {
+			t.Errorf("want %s, got %s", ReasonBudgetExhausted, got)
+		}
+	})
+}
+
+func TestDo_Reason(t *testing.T) {
+	t.Run("not wrapped by default", func(t *testing.T) {
+		policy := &Policy{MaxCount: 3}
+		err := policy.Do(context.Background(), func() error {
+			return io.ErrUnexpectedEOF
+		})
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("want %v, got %v", io.ErrUnexpectedEOF, err)
+		}
+
+		err = policy.Do(context.Background(), func() error {
+			return MarkPermanent(io.EOF)
+		})
+		if err != io.EOF {
+			t.Errorf("want %v, got %v", io.EOF, err)
+		}
+	})
+
+	t.Run("max count exceeded", func(t *testing.T) {
+		policy := &Policy{MaxCount: 3, WrapReason: true}
+		err := policy.Do(context.Background(), func() error {
+			return io.ErrUnexpectedEOF
+		})
+		if !errors.Is(err, ErrMaxCountExceeded) {
+			t.Errorf("want %v to be wrapped in %v", ErrMaxCountExceeded, err)
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("want %v to be wrapped in %v", io.ErrUnexpectedEOF, err)
+		}
+		if err.Error() != io.ErrUnexpectedEOF.Error() {
+			t.Errorf("want %q, got %q", io.ErrUnexpectedEOF.Error(), err.Error())
+		}
+	})
+
+	t.Run("permanent error", func(t *testing.T) {
+		policy := &Policy{MaxCount: 3, WrapReason: true}
+		err := policy.Do(context.Background(), func() error {
+			return MarkPermanent(io.EOF)
+		})
+		if !errors.Is(err, ErrPermanent) {
+			t.Errorf("want %v to be wrapped in %v", ErrPermanent, err)
+		}
+		if errors.Is(err, ErrMaxCountExceeded) {
+			t.Errorf("want %v not to be wrapped in %v", ErrMaxCountExceeded, err)
+		}
+	})
+
+	t.Run("deadline would exceed", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+		defer cancel()
+		policy := &Policy{MinDelay: 2 * time.Hour, WrapReason: true}
+		err := policy.Do(ctx, func() error {
+			return io.ErrUnexpectedEOF
+		})
+		if !errors.Is(err, ErrDeadlineWouldExceed) {
+			t.Errorf("want %v to be wrapped in %v", ErrDeadlineWouldExceed, err)
+		}
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("want %v to be wrapped in %v", context.DeadlineExceeded, err)
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("want %v to be wrapped in %v", io.ErrUnexpectedEOF, err)
+		}
+	})
+
+	t.Run("aggregate errors", func(t *testing.T) {
+		policy := &Policy{MaxCount: 3, AggregateErrors: true}
+		err := policy.Do(context.Background(), func() error {
+			return io.ErrUnexpectedEOF
+		})
+		var retryErr *RetryError
+		if !errors.As(err, &retryErr) {
+			t.Fatalf("want *RetryError, got %T", err)
+		}
+		if retryErr.Reason != ReasonMaxCountExceeded {
+			t.Errorf("want %s, got %s", ReasonMaxCountExceeded, retryErr.Reason)
+		}
+		if !errors.Is(err, ErrMaxCountExceeded) {
+			t.Errorf("want %v to be wrapped in %v", ErrMaxCountExceeded, err)
+		}
+	})
+}
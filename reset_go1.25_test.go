@@ -0,0 +1,156 @@
+//go:build go1.25
+// +build go1.25
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestRetrier_Reset(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxDelay: time.Minute,
+			MaxCount: 3,
+		}
+		retrier := policy.Start(context.Background())
+		for retrier.Continue() {
+		}
+		if got := retrier.Reason(); got != ReasonMaxCountExceeded {
+			t.Errorf("want %s, got %s", ReasonMaxCountExceeded, got)
+		}
+
+		retrier.Reset()
+		if got := retrier.Reason(); got != ReasonNone {
+			t.Errorf("want %s, got %s", ReasonNone, got)
+		}
+
+		// the first attempt starts without sleeping.
+		start := time.Now()
+		if !retrier.Continue() {
+			t.Fatal("want to continue, but not")
+		}
+		if d := time.Since(start); d != 0 {
+			t.Errorf("want %s, got %s", time.Duration(0), d)
+		}
+
+		// the delay starts from MinDelay again.
+		if !retrier.Continue() {
+			t.Fatal("want to continue, but not")
+		}
+		if d := time.Since(start); d != time.Second {
+			t.Errorf("want %s, got %s", time.Second, d)
+		}
+		if got := retrier.Attempt(); got != 2 {
+			t.Errorf("want %d, got %d", 2, got)
+		}
+	})
+}
+
+func TestRetrier_ResetAfter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:   time.Second,
+			MaxDelay:   time.Minute,
+			MaxCount:   4,
+			ResetAfter: 10 * time.Second,
+		}
+
+		// the connection is lost immediately in the first 3 attempts,
+		// and it is healthy for 20 seconds in the 4th attempt.
+		lifetimes := []time.Duration{0, 0, 0, 20 * time.Second, 0, 0}
+		want := []time.Duration{
+			0, time.Second, 3 * time.Second, 7 * time.Second,
+
+			// reset
+			28 * time.Second, 30 * time.Second,
+		}
+		var got []time.Duration
+		var attempts []int
+		start := time.Now()
+		retrier := policy.Start(context.Background())
+		for i := 0; i < len(want) && retrier.Continue(); i++ {
+			got = append(got, time.Since(start))
+			attempts = append(attempts, retrier.Attempt())
+			time.Sleep(lifetimes[i])
+		}
+
+		if len(got) != len(want) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("#%d: want %s, got %s", i, want[i], got[i])
+			}
+		}
+		wantAttempts := []int{1, 2, 3, 4, 2, 3}
+		for i := range wantAttempts {
+			if attempts[i] != wantAttempts[i] {
+				t.Errorf("#%d: want attempt %d, got %d", i, wantAttempts[i], attempts[i])
+			}
+		}
+	})
+}
+
+func TestDo_ResetAfterRetryAfter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		var rec hookRecorder
+		policy := &Policy{
+			MinDelay:        time.Second,
+			MaxDelay:        time.Minute,
+			MaxCount:        2,
+			ResetAfter:      10 * time.Second,
+			AggregateErrors: true,
+			Hooks:           rec.hooks(),
+		}
+
+		// the first attempt is healthy for 20 seconds, and then it asks to retry after 30 seconds.
+		var starts []time.Duration
+		start := time.Now()
+		err := policy.Do(t.Context(), func() error {
+			starts = append(starts, time.Since(start))
+			if len(starts) == 1 {
+				time.Sleep(20 * time.Second)
+				return MarkTemporaryAfter(io.ErrUnexpectedEOF, 30*time.Second)
+			}
+			return io.EOF
+		})
+
+		want := []time.Duration{0, 50 * time.Second}
+		if len(starts) != len(want) {
+			t.Fatalf("want %v, got %v", want, starts)
+		}
+		for i := range want {
+			if starts[i] != want[i] {
+				t.Errorf("#%d: want %s, got %s", i, want[i], starts[i])
+			}
+		}
+
+		if len(rec.retries) != 1 {
+			t.Fatalf("want 1 retry, got %d", len(rec.retries))
+		}
+		if e := rec.retries[0]; !errors.Is(e.Err, io.ErrUnexpectedEOF) || e.Delay != 30*time.Second {
+			t.Errorf("want %v and %s, got %v and %s", io.ErrUnexpectedEOF, 30*time.Second, e.Err, e.Delay)
+		}
+
+		var rerr *RetryError
+		if !errors.As(err, &rerr) {
+			t.Fatalf("want *RetryError, got %T", err)
+		}
+		if len(rerr.Attempts) != 2 {
+			t.Fatalf("want 2 attempts, got %d", len(rerr.Attempts))
+		}
+		if !errors.Is(rerr.Attempts[0].Err, io.ErrUnexpectedEOF) {
+			t.Errorf("want %v, got %v", io.ErrUnexpectedEOF, rerr.Attempts[0].Err)
+		}
+		if !errors.Is(rerr.Attempts[1].Err, io.EOF) {
+			t.Errorf("want %v, got %v", io.EOF, rerr.Attempts[1].Err)
+		}
+	})
+}
@@ -3,12 +3,18 @@ package retry
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"math"
 	"math/rand/v2"
 	"time"
 )
 
 // Policy is a retry policy.
 type Policy struct {
+	// Name is the name of the policy.
+	// It is used for logging and metrics.
+	Name string
+
 	// MinDelay is a first delay for retrying.
 	// Zero or negative value means no delay.
 	MinDelay time.Duration
@@ -25,34 +31,169 @@ type Policy struct {
 	// Zero means no jitter.
 	// Negative value shorten the delay.
 	Jitter time.Duration
+
+	// MaxElapsed is the time budget for retrying, measured from Policy.Start.
+	// If the next delay would exceed the budget, the retrier gives up and [Retrier.Err] returns [ErrMaxElapsed].
+	// Zero or negative value means no limit.
+	MaxElapsed time.Duration
+
+	// DeadlineStrategy decides what the retrier does when the next delay would exceed the deadline of the context.
+	// The default is [DeadlineGiveUp].
+	DeadlineStrategy DeadlineStrategy
+
+	// DeadlineMargin is the time reserved for the last attempt by [DeadlineShortenDelay].
+	// Zero or negative value means 100 milliseconds.
+	DeadlineMargin time.Duration
+
+	// ResetAfter resets the retrier if the last attempt started ResetAfter or more ago,
+	// e.g. the connection of a reconnect loop has been healthy for a while.
+	// The next delay starts from the first one again, and [Policy.MaxCount] and [Policy.MaxElapsed] are counted from the reset.
+	// The delay specified by the last error, e.g. [MarkTemporaryAfter], is still respected.
+	// Zero or negative value means no reset.
+	ResetAfter time.Duration
+
+	// AttemptTimeout is the timeout for each attempt of [Policy.DoContext] and [DoValueContext].
+	// Zero or negative value means no timeout.
+	AttemptTimeout time.Duration
+
+	// Budget limits the number of retries shared by the retriers if it is not nil.
+	// If the budget is exhausted, the retrier gives up and [Retrier.Err] returns [ErrBudgetExhausted].
+	Budget *Budget
+
+	// Breaker stops the attempts while the circuit is open if it is not nil.
+	// If the circuit is open, the retrier gives up without sleeping and [Retrier.Err] returns [ErrCircuitOpen].
+	Breaker *CircuitBreaker
+
+	// Throttler rejects the attempts locally while the backend rejects many requests, if it is not nil.
+	// The rejection is a permanent error, and [Retrier.Err] returns [ErrThrottled] without sleeping.
+	Throttler *Throttler
+
+	// Classifier decides whether an error is retryable.
+	// It is consulted for the errors that are not marked by [MarkPermanent], [MarkTemporary] or [MarkTemporaryAfter].
+	// If Classifier is nil or it returns [Undecided], the error is retried.
+	Classifier Classifier
+
+	// MaxRetryAfter is the upper limit of the delay specified by errors and classifiers.
+	// See [MarkTemporaryAfter] and [RetryAfter] for details.
+	// Zero or negative value means no limit.
+	MaxRetryAfter time.Duration
+
+	// AggregateErrors makes Do and DoValue return a [*RetryError] that holds the errors of all attempts,
+	// instead of only the last error.
+	AggregateErrors bool
+
+	// WrapReason makes Do and DoValue wrap the returned error with the sentinel error of [Retrier.Reason],
+	// e.g. [ErrMaxCountExceeded], [ErrPermanent] or [ErrDeadlineWouldExceed].
+	// The message of the error is kept, but the error can't be compared with ==. Use [errors.Is] instead.
+	WrapReason bool
+
+	// Hooks is the set of functions called on retry events.
+	Hooks *Hooks
+
+	// Logger logs retry events if it is not nil.
+	// Each retry is logged at LogLevel, and giving up is logged at [slog.LevelWarn].
+	Logger *slog.Logger
+
+	// LogLevel is the level for logging retries.
+	// The default is [slog.LevelInfo].
+	LogLevel slog.Level
+
+	// Metrics records the metrics of retrying if it is not nil.
+	Metrics Metrics
+
+	// Clock is the source of the current time and timers.
+	// If Clock is nil, the system clock is used.
+	// Note that the deadline of the context is compared with the time of Clock.
+	Clock Clock
+
+	// Multiplier is the growth factor of the exponential back off.
+	// Zero or negative value means 2.
+	Multiplier float64
+
+	// Exponent switches the back off to polynomial growth if it is positive.
+	// The delay before the n-th retry is MinDelay * n^Exponent, and Multiplier is ignored.
+	Exponent float64
+
+	// JitterMode is the algorithm for randomizing delays.
+	// The default is JitterOffset, which adds a random duration in the range of Jitter.
+	JitterMode JitterMode
+
+	// Backoff computes the delays between attempts.
+	// If Backoff is nil, the delay starts from MinDelay and doubles on each retry up to MaxDelay.
+	// Otherwise the delay returned by Backoff is used instead of MinDelay,
+	// and it is capped at MaxDelay if MaxDelay is positive.
+	Backoff Backoff
 }
 
+// ErrMaxElapsed is returned by [Retrier.Err] when the retrier gives up because of [Policy.MaxElapsed].
+var ErrMaxElapsed = errors.New("retry: max elapsed time exceeded")
+
 // Retrier handles retrying.
 type Retrier struct {
-	ctx      context.Context
-	policy   *Policy
-	count    int
-	maxCount int
-	delay    time.Duration
-	maxDelay time.Duration
-	timer    *time.Timer
-	err      error
+	ctx        context.Context
+	policy     *Policy
+	retryAfter time.Duration
+	target     *retryAfter
+	tmp        *temporary
+	count      int
+	maxCount   int
+	delay      time.Duration
+	maxDelay   time.Duration
+	sleep      time.Duration
+	totalDelay time.Duration
+	start      time.Time
+	attempt    time.Time
+	errs       []AttemptError
+	lastErr    error
+	done       bool
+	end        time.Time
+	reason     Reason
+	final      bool
+	fitted     bool
+	clock      Clock
+	timer      Timer
+	err        error
 }
 
 // Start starts retrying
 func (p *Policy) Start(ctx context.Context) *Retrier {
 	maxDelay := p.MaxDelay
-	if maxDelay < p.MinDelay {
+	if maxDelay < p.MinDelay && p.Backoff == nil {
 		maxDelay = p.MinDelay
 	}
-	return &Retrier{
+	clock := p.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+	r := &Retrier{
 		ctx:      ctx,
 		policy:   p,
-		count:    0,
 		maxCount: p.MaxCount,
-		delay:    p.MinDelay,
 		maxDelay: maxDelay,
+		clock:    clock,
 	}
+	r.Reset()
+	return r
+}
+
+// Reset resets the retrier to the initial state.
+// The next call of [Retrier.Continue] returns true without sleeping, and the delay starts from the first one again.
+func (r *Retrier) Reset() {
+	r.retryAfter = 0
+	r.count = 0
+	r.delay = r.nextDelay(1, 0)
+	r.sleep = 0
+	r.totalDelay = 0
+	r.start = r.clock.Now()
+	r.attempt = time.Time{}
+	r.errs = nil
+	r.lastErr = nil
+	r.done = false
+	r.end = time.Time{}
+	r.reason = ReasonNone
+	r.final = false
+	r.fitted = false
+	r.err = nil
 }
 
 // Do executes f with retrying policy.
@@ -62,43 +203,31 @@ func (p *Policy) Start(ctx context.Context) *Retrier {
 // Error handling:
 //   - [MarkPermanent]: stops retrying immediately and returns the unwrapped error
 //   - [MarkTemporary]: continues retrying (explicit marker for retryable errors)
-//   - Unmarked errors: treated as temporary and retried
+//   - Unmarked errors: classified by [Policy.Classifier], or treated as temporary and retried
+//
+// If the error has the RetryAfter() time.Duration method, e.g. the error marked by [MarkTemporaryAfter],
+// the returned duration is used as the next delay.
+//
+// If ctx is done before f succeeds, the returned error wraps both the error of ctx and the last error returned by f.
+// If [Policy.WrapReason] is set, the returned error also wraps the sentinel error of the reason,
+// e.g. [ErrMaxCountExceeded] or [ErrPermanent], keeping the message of the last error.
 func (p *Policy) Do(ctx context.Context, f func() error) error {
-	var err error
-	var target *temporary
-
-	retrier := p.Start(ctx)
-	for retrier.Continue() {
-		err = f()
-		if err == nil {
-			return nil
-		}
-
-		// short cut for calling Unwrap
-		if err, ok := err.(*myError); ok {
-			if err.tmp {
-				continue
-			}
-			return err.error
-		}
+	_, err := doValue(ctx, p, func(context.Context) (struct{}, error) {
+		return struct{}{}, f()
+	})
+	return err
+}
 
-		if target == nil {
-			// lazy allocation of target
-			target = new(temporary)
-		}
-		if errors.As(err, target) {
-			if !(*target).temporary() {
-				return err
-			}
-		}
-	}
-	if err := retrier.err; err != nil {
-		return err
-	}
-	if err, ok := err.(*myError); ok {
-		// Unwrap the error if it's marked as temporary.
-		return err.error
-	}
+// DoContext is like [Policy.Do], but f receives a context for each attempt.
+// The context is derived from ctx, and it has a deadline if [Policy.AttemptTimeout] is positive.
+//
+// If an attempt fails because its own deadline is exceeded, DoContext retries it
+// even if the error is marked by [MarkPermanent].
+// On the other hand, the cancellation of ctx stops retrying.
+func (p *Policy) DoContext(ctx context.Context, f func(ctx context.Context) error) error {
+	_, err := doValue(ctx, p, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, f(ctx)
+	})
 	return err
 }
 
@@ -147,34 +276,257 @@ func MarkTemporary(err error) error {
 	return &myError{err, true}
 }
 
+// MarkTemporaryAfter wraps an error as a temporary error that should be retried after d.
+// [Policy.Do] and [DoValue] use d as the next delay instead of the back off.
+// See [Policy.MaxRetryAfter] for the upper limit of d.
+func MarkTemporaryAfter(err error, d time.Duration) error {
+	return &retryAfterError{err, d}
+}
+
 // Continue returns whether retrying should be continued.
 func (r *Retrier) Continue() bool {
+	if limit := r.policy.ResetAfter; limit > 0 && r.count > 0 && r.since(r.attempt) >= limit {
+		// the last attempt has been stable for a while.
+		// regard it as the first attempt, and sleep for the first delay.
+		// the error of the last attempt and its retry-after delay are kept.
+		r.count = 1
+		r.delay = r.nextDelay(1, 0)
+		r.start = r.clock.Now()
+		r.attempt = r.start
+		r.final = false
+	}
+
 	r.count++
 	if r.count == 1 {
-		// always execute at first.
+		// always execute at first, unless the circuit is open or the attempt is throttled.
+		if !r.throttle() {
+			return r.stop(ErrThrottled)
+		}
+		if b := r.policy.Breaker; b != nil && !b.allow(r.clock.Now()) {
+			return r.stop(ErrCircuitOpen)
+		}
+		r.attempt = r.start
+		r.notifyAttempt()
 		return true
 	}
 
+	r.sleep = r.nextSleep()
+	r.fitDeadline()
+	if ok, err := r.check(r.sleep); !ok {
+		return r.stop(err)
+	}
+	if !r.throttle() {
+		// reject it before sleeping.
+		return r.stop(ErrThrottled)
+	}
+
+	r.notifyRetry()
+	if err := r.sleepContext(r.ctx, r.sleep); err != nil {
+		return r.stop(err)
+	}
+	if ok, err := r.acquire(); !ok {
+		// another retrier took the permission while sleeping.
+		return r.stop(err)
+	}
+	r.begin()
+	return true
+}
+
+// nextSleep returns the delay before the next attempt.
+func (r *Retrier) nextSleep() time.Duration {
+	if d := r.retryAfter; d > 0 {
+		// the delay is specified by the error.
+		r.retryAfter = 0
+		return d
+	}
+	return r.jitter(r.delay)
+}
+
+// check reports whether the r.count-th attempt can start after sleeping d.
+// If not, err is the reason, and it is nil if the retry limit is reached.
+func (r *Retrier) check(d time.Duration) (ok bool, err error) {
 	if r.maxCount > 0 && r.count > r.maxCount {
 		// max retry count is exceeded.
-		return false
+		return false, nil
 	}
 
-	if err := r.sleepContext(r.ctx, r.delay+r.policy.randomJitter()); err != nil {
-		r.err = err
-		return false
+	if err := r.ctx.Err(); err != nil {
+		return false, err
 	}
 
-	// exponential back off
-	r.delay *= 2
-	if r.delay > r.maxDelay {
-		r.delay = r.maxDelay
+	if limit := r.policy.MaxElapsed; limit > 0 && r.since(r.start)+max(d, 0) > limit {
+		// the time budget is exhausted.
+		return false, ErrMaxElapsed
 	}
 
-	return true
+	if b := r.policy.Budget; b != nil && !b.available(r.clock.Now()) {
+		// the retry budget is exhausted.
+		// don't withdraw it here, because the sleep may be canceled.
+		return false, ErrBudgetExhausted
+	}
+
+	if b := r.policy.Breaker; b != nil && !b.ready(r.clock.Now().Add(max(d, 0))) {
+		// the dependency seems to be down, and it will be when the attempt starts.
+		return false, ErrCircuitOpen
+	}
+	return true, nil
+}
+
+// acquire takes the permissions for the next attempt from the breaker and the budget after sleeping.
+func (r *Retrier) acquire() (ok bool, err error) {
+	now := r.clock.Now()
+	b := r.policy.Breaker
+	if b != nil && !b.allow(now) {
+		// the dependency seems to be down.
+		return false, ErrCircuitOpen
+	}
+
+	if budget := r.policy.Budget; budget != nil && !budget.withdraw(now) {
+		// the retry budget is exhausted.
+		if b != nil {
+			b.release()
+		}
+		return false, ErrBudgetExhausted
+	}
+	return true, nil
+}
+
+// reportFailure reports the failed attempt to the breaker.
+// permanent reports whether the attempt failed with a permanent error.
+func (r *Retrier) reportFailure(permanent bool) {
+	b := r.policy.Breaker
+	if b == nil {
+		return
+	}
+	if permanent || r.ctx.Err() != nil {
+		// it is not a failure of the dependency.
+		b.release()
+		return
+	}
+	b.failure(r.clock.Now())
+}
+
+// begin starts the next attempt after sleeping.
+func (r *Retrier) begin() {
+	r.totalDelay += max(r.sleep, 0)
+	r.delay = r.nextDelay(r.count, r.delay)
+	r.attempt = r.clock.Now()
+	r.notifyAttempt()
+}
+
+// stop stops retrying because of err.
+// err is nil if the retry limit is reached.
+func (r *Retrier) stop(err error) bool {
+	// the last call of Continue doesn't start any attempt.
+	r.count--
+	r.halt(err)
+	return false
+}
+
+// halt gives up retrying because of err.
+func (r *Retrier) halt(err error) {
+	r.reason = r.reasonOf(err)
+	r.err = err
+	// the rejection of the throttler is a permanent error.
+	r.notifyGiveUp(r.reason == ReasonThrottled)
+}
+
+// nextDelay returns the delay before the attempt-th retry.
+func (r *Retrier) nextDelay(attempt int, prev time.Duration) time.Duration {
+	if b := r.policy.Backoff; b != nil {
+		d := b.Next(attempt, prev)
+		if r.maxDelay > 0 && d > r.maxDelay {
+			d = r.maxDelay
+		}
+		return d
+	}
+
+	if attempt <= 1 {
+		return r.policy.MinDelay
+	}
+
+	var d time.Duration
+	if k := r.policy.Exponent; k > 0 {
+		// polynomial back off
+		d = scaleDuration(r.policy.MinDelay, math.Pow(float64(attempt), k))
+	} else {
+		// exponential back off
+		m := r.policy.Multiplier
+		if m <= 0 {
+			m = 2
+		}
+		d = scaleDuration(prev, m)
+	}
+	if d > r.maxDelay {
+		d = r.maxDelay
+	}
+	return d
+}
+
+// Attempt returns the number of attempts that have been started.
+// It is zero before the first call of [Retrier.Continue].
+func (r *Retrier) Attempt() int {
+	return r.count
+}
+
+// Remaining returns the number of attempts that remain under [Policy.MaxCount].
+// It returns -1 if the number of attempts is unlimited.
+func (r *Retrier) Remaining() int {
+	if r.maxCount <= 0 {
+		return -1
+	}
+	return max(r.maxCount-r.count, 0)
+}
+
+// IsLast reports whether the current attempt is the last one allowed by [Policy.MaxCount].
+func (r *Retrier) IsLast() bool {
+	return r.maxCount > 0 && r.count >= r.maxCount
+}
+
+// NextDelay returns the delay before the next attempt, excluding jitter.
+func (r *Retrier) NextDelay() time.Duration {
+	return r.delay
+}
+
+// Elapsed returns the time elapsed since [Policy.Start] or the last reset.
+// It stops growing when the retrier succeeds or gives up.
+func (r *Retrier) Elapsed() time.Duration {
+	if !r.end.IsZero() {
+		return r.end.Sub(r.start)
+	}
+	return r.since(r.start)
+}
+
+// Stats is a snapshot of the statistics of a [Retrier].
+type Stats struct {
+	// Attempts is the number of attempts that have been started.
+	Attempts int
+
+	// TotalDelay is the total time slept between attempts.
+	TotalDelay time.Duration
+
+	// Elapsed is the time elapsed since [Policy.Start] or the last reset, until the retrier succeeds or gives up.
+	Elapsed time.Duration
+
+	// Err is the error returned by [Retrier.Err].
+	Err error
+}
+
+// Stats returns the snapshot of the statistics.
+// It is typically called after the loop of [Retrier.Continue] ends.
+func (r *Retrier) Stats() Stats {
+	return Stats{
+		Attempts:   r.count,
+		TotalDelay: r.totalDelay,
+		Elapsed:    r.Elapsed(),
+		Err:        r.err,
+	}
 }
 
 // Err return the error that occurred during deploy.
+// See [Retrier.Reason] for the reason why the retrier stopped retrying.
+// If the next delay would exceed the deadline of the context, Err returns [context.DeadlineExceeded],
+// and Reason returns [ReasonDeadlineWouldExceed].
 func (r *Retrier) Err() error {
 	return r.err
 }
@@ -190,8 +542,8 @@ func (r *Retrier) sleepContext(ctx context.Context, d time.Duration) error {
 	if d <= 0 {
 		return nil
 	}
-	if deadline, ok := ctx.Deadline(); ok {
-		if time.Until(deadline) < d {
+	if deadline, ok := ctx.Deadline(); ok && !r.fitted {
+		if deadline.Sub(r.clock.Now()) < d {
 			// skip sleeping.
 			// because sleepContext returns context.DeadlineExceeded even if a sleep is got.
 			return context.DeadlineExceeded
@@ -200,14 +552,14 @@ func (r *Retrier) sleepContext(ctx context.Context, d time.Duration) error {
 
 	t := r.timer
 	if t == nil {
-		t = time.NewTimer(d)
+		t = r.clock.NewTimer(d)
 		r.timer = t
 	} else {
 		t.Reset(d)
 	}
 	defer t.Stop()
 	select {
-	case <-t.C:
+	case <-t.C():
 		return nil
 	case <-ctx.Done():
 		r.timer = nil
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"testing"
 	"testing/synctest"
 	"time"
@@ -137,6 +138,100 @@ func TestRetry_WithNegativeJitter(t *testing.T) {
 	})
 }
 
+func TestRetry_WithBackoff(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		want := []time.Duration{
+			// sleepContext is not called as first
+			0,
+
+			// linear back off
+			time.Second, 3 * time.Second, 5 * time.Second, 7 * time.Second,
+
+			// reach MaxDelay
+			8 * time.Second, 8 * time.Second,
+		}
+		policy := &Policy{
+			MinDelay: time.Minute, // ignored
+			MaxDelay: 8 * time.Second,
+			Backoff:  LinearBackoff(time.Second, 2*time.Second),
+		}
+		retrier := policy.Start(t.Context())
+		for i := range want {
+			start := time.Now()
+			if !retrier.Continue() {
+				t.Error("want to continue, but not")
+			}
+			delay := time.Since(start)
+			if delay != want[i] {
+				t.Errorf("want %s, got %s", want[i], delay)
+			}
+		}
+	})
+}
+
+func TestRetry_WithMultiplier(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		want := []time.Duration{
+			// sleepContext is not called as first
+			0,
+
+			// exponential back off
+			time.Second, 1500 * time.Millisecond, 2250 * time.Millisecond, 3375 * time.Millisecond,
+
+			// reach MaxDelay
+			4 * time.Second, 4 * time.Second,
+		}
+		policy := &Policy{
+			MinDelay:   time.Second,
+			MaxDelay:   4 * time.Second,
+			Multiplier: 1.5,
+		}
+		retrier := policy.Start(t.Context())
+		for i := range want {
+			start := time.Now()
+			if !retrier.Continue() {
+				t.Error("want to continue, but not")
+			}
+			delay := time.Since(start)
+			if delay != want[i] {
+				t.Errorf("want %s, got %s", want[i], delay)
+			}
+		}
+	})
+}
+
+func TestRetry_WithExponent(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		want := []time.Duration{
+			// sleepContext is not called as first
+			0,
+
+			// polynomial back off
+			time.Second, 4 * time.Second, 9 * time.Second, 16 * time.Second, 25 * time.Second,
+
+			// reach MaxDelay
+			30 * time.Second, 30 * time.Second,
+		}
+		policy := &Policy{
+			MinDelay:   time.Second,
+			MaxDelay:   30 * time.Second,
+			Multiplier: 10, // ignored
+			Exponent:   2,
+		}
+		retrier := policy.Start(t.Context())
+		for i := range want {
+			start := time.Now()
+			if !retrier.Continue() {
+				t.Error("want to continue, but not")
+			}
+			delay := time.Since(start)
+			if delay != want[i] {
+				t.Errorf("want %s, got %s", want[i], delay)
+			}
+		}
+	})
+}
+
 func TestRetry_WithMaxCount(t *testing.T) {
 	synctest.Test(t, func(t *testing.T) {
 		policy := &Policy{
@@ -168,6 +263,113 @@ func TestRetry_WithMaxCount(t *testing.T) {
 	})
 }
 
+func TestRetry_WithMaxElapsed(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:   time.Second,
+			MaxDelay:   time.Minute,
+			MaxElapsed: 10 * time.Second,
+		}
+		start := time.Now()
+		retrier := policy.Start(t.Context())
+
+		// 0s, 1s, 2s and 4s
+		for range 4 {
+			if !retrier.Continue() {
+				t.Error("want to continue, but got not")
+			}
+		}
+
+		// the next delay 8s exceeds the budget.
+		if retrier.Continue() {
+			t.Error("want not to continue, but do")
+		}
+		if err := retrier.Err(); err != ErrMaxElapsed {
+			t.Errorf("want %v, got %v", ErrMaxElapsed, err)
+		}
+
+		delay := time.Since(start)
+		if delay != 7*time.Second {
+			t.Errorf("want 7s, got %s", delay)
+		}
+	})
+}
+
+func TestRetrier_Accessors(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxDelay: time.Minute,
+			MaxCount: 3,
+		}
+		retrier := policy.Start(t.Context())
+		if got := retrier.Attempt(); got != 0 {
+			t.Errorf("Attempt: want %d, got %d", 0, got)
+		}
+		if got := retrier.Remaining(); got != 3 {
+			t.Errorf("Remaining: want %d, got %d", 3, got)
+		}
+
+		want := []struct {
+			attempt   int
+			remaining int
+			isLast    bool
+			nextDelay time.Duration
+			elapsed   time.Duration
+		}{
+			{1, 2, false, time.Second, 0},
+			{2, 1, false, 2 * time.Second, time.Second},
+			{3, 0, true, 4 * time.Second, 3 * time.Second},
+		}
+		for _, w := range want {
+			if !retrier.Continue() {
+				t.Fatal("want to continue, but not")
+			}
+			if got := retrier.Attempt(); got != w.attempt {
+				t.Errorf("Attempt: want %d, got %d", w.attempt, got)
+			}
+			if got := retrier.Remaining(); got != w.remaining {
+				t.Errorf("Remaining: want %d, got %d", w.remaining, got)
+			}
+			if got := retrier.IsLast(); got != w.isLast {
+				t.Errorf("IsLast: want %t, got %t", w.isLast, got)
+			}
+			if got := retrier.NextDelay(); got != w.nextDelay {
+				t.Errorf("NextDelay: want %s, got %s", w.nextDelay, got)
+			}
+			if got := retrier.Elapsed(); got != w.elapsed {
+				t.Errorf("Elapsed: want %s, got %s", w.elapsed, got)
+			}
+		}
+
+		if retrier.Continue() {
+			t.Fatal("want not to continue, but do")
+		}
+		// the statistics are frozen after the retrier gives up.
+		time.Sleep(time.Second)
+		want2 := Stats{
+			Attempts:   3,
+			TotalDelay: 3 * time.Second,
+			Elapsed:    3 * time.Second,
+		}
+		if got := retrier.Stats(); got != want2 {
+			t.Errorf("Stats: want %#v, got %#v", want2, got)
+		}
+	})
+}
+
+func TestRetrier_Remaining_Unlimited(t *testing.T) {
+	policy := &Policy{}
+	retrier := policy.Start(t.Context())
+	retrier.Continue()
+	if got := retrier.Remaining(); got != -1 {
+		t.Errorf("want %d, got %d", -1, got)
+	}
+	if retrier.IsLast() {
+		t.Error("want not to be the last attempt")
+	}
+}
+
 func TestSleepContext(t *testing.T) {
 	t.Run("normal", func(t *testing.T) {
 		synctest.Test(t, func(t *testing.T) {
@@ -349,10 +551,13 @@ func TestDo_Deadline(t *testing.T) {
 
 		start := time.Now()
 		err := policy.Do(ctx, func() error {
-			return errors.New("some error")
+			return io.ErrUnexpectedEOF
 		})
-		if err != context.DeadlineExceeded {
-			t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("want %v to be wrapped in %v", context.DeadlineExceeded, err)
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("want %v to be wrapped in %v", io.ErrUnexpectedEOF, err)
 		}
 		d := time.Since(start)
 		if d != 0 {
@@ -360,3 +565,81 @@ func TestDo_Deadline(t *testing.T) {
 		}
 	})
 }
+
+func TestDo_MaxElapsed(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:   time.Second,
+			MaxElapsed: 5 * time.Second,
+		}
+
+		var count int
+		err := policy.Do(t.Context(), func() error {
+			count++
+			// elapsed time of the operation is also counted.
+			time.Sleep(time.Second)
+			return errors.New("some error")
+		})
+		if !errors.Is(err, ErrMaxElapsed) {
+			t.Errorf("want %v to be wrapped in %v", ErrMaxElapsed, err)
+		}
+		if count != 3 {
+			t.Errorf("want %d, got %d", 3, count)
+		}
+	})
+}
+
+func TestDoContext_AttemptTimeout(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:       time.Second,
+			AttemptTimeout: 5 * time.Second,
+		}
+
+		start := time.Now()
+		count := 0
+		err := policy.DoContext(t.Context(), func(ctx context.Context) error {
+			count++
+			if count < 3 {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 3 {
+			t.Errorf("want %d, got %d", 3, count)
+		}
+
+		// 5s (timeout) + 1s (delay) + 5s (timeout) + 1s (delay)
+		d := time.Since(start)
+		if d != 12*time.Second {
+			t.Errorf("want 12s, got %s", d)
+		}
+	})
+}
+
+func TestDoContext_ParentDeadline(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			AttemptTimeout: 5 * time.Second,
+		}
+		ctx, cancel := context.WithTimeout(t.Context(), 3*time.Second)
+		defer cancel()
+
+		count := 0
+		err := policy.DoContext(ctx, func(ctx context.Context) error {
+			count++
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("want %v to be wrapped in %v", context.DeadlineExceeded, err)
+		}
+		if count != 1 {
+			t.Errorf("want %d, got %d", 1, count)
+		}
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"testing"
 	"time"
 )
@@ -153,6 +154,42 @@ func TestRetry_WithNegativeJitter(t *testing.T) {
 	}
 }
 
+func TestRetry_WithBackoff(t *testing.T) {
+	var delay time.Duration
+	testSleep = func(ctx context.Context, d time.Duration) error {
+		delay = d
+		return nil
+	}
+	defer func() {
+		testSleep = nil
+	}()
+
+	want := []time.Duration{
+		// sleepContext is not called as first
+		0,
+
+		// linear back off
+		time.Second, 3 * time.Second, 5 * time.Second, 7 * time.Second,
+
+		// reach MaxDelay
+		8 * time.Second, 8 * time.Second,
+	}
+	policy := &Policy{
+		MinDelay: time.Minute, // ignored
+		MaxDelay: 8 * time.Second,
+		Backoff:  LinearBackoff(time.Second, 2*time.Second),
+	}
+	retrier := policy.Start(context.Background())
+	for i := 0; i < len(want); i++ {
+		if !retrier.Continue() {
+			t.Error("want to continue, but not")
+		}
+		if delay != want[i] {
+			t.Errorf("want %s, got %s", want[i], delay)
+		}
+	}
+}
+
 func TestRetry_WithMaxCount(t *testing.T) {
 	policy := &Policy{
 		MaxCount: 3,
@@ -341,10 +378,13 @@ func TestDo_Deadline(t *testing.T) {
 
 	start := time.Now()
 	err := policy.Do(ctx, func() error {
-		return errors.New("some error")
+		return io.ErrUnexpectedEOF
 	})
-	if err != context.DeadlineExceeded {
-		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want %v to be wrapped in %v", context.DeadlineExceeded, err)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("want %v to be wrapped in %v", io.ErrUnexpectedEOF, err)
 	}
 	d := time.Since(start)
 	if d > 500*time.Millisecond {
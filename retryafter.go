@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+// retryAfter is the interface of errors that specify the delay before the next attempt.
+// e.g. the Retry-After header of HTTP, RetryInfo of gRPC, and so on.
+type retryAfter interface {
+	RetryAfter() time.Duration
+}
+
+var (
+	_ temporary  = (*retryAfterError)(nil)
+	_ retryAfter = (*retryAfterError)(nil)
+)
+
+type retryAfterError struct {
+	error
+	d time.Duration
+}
+
+func (e *retryAfterError) temporary() bool {
+	return true
+}
+
+// RetryAfter returns the delay before the next attempt.
+func (e *retryAfterError) RetryAfter() time.Duration {
+	return e.d
+}
+
+// Unwrap implements errors.Wrapper.
+func (e *retryAfterError) Unwrap() error {
+	return e.error
+}
+
+// setRetryAfter sets the next delay if err specifies it.
+func (r *Retrier) setRetryAfter(err error) {
+	if r.target == nil {
+		// lazy allocation of target
+		r.target = new(retryAfter)
+	}
+	if !errors.As(err, r.target) {
+		return
+	}
+
+	r.setNextDelay((*r.target).RetryAfter())
+}
+
+// setNextDelay overrides the next delay with d.
+// d is capped at [Policy.MaxRetryAfter].
+func (r *Retrier) setNextDelay(d time.Duration) {
+	if limit := r.policy.MaxRetryAfter; limit > 0 && d > limit {
+		d = limit
+	}
+	r.retryAfter = d
+}
@@ -0,0 +1,99 @@
+//go:build go1.25
+// +build go1.25
+
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+type throttlingError struct {
+	d time.Duration
+}
+
+func (e *throttlingError) Error() string {
+	return fmt.Sprintf("throttled: retry after %s", e.d)
+}
+
+func (e *throttlingError) RetryAfter() time.Duration {
+	return e.d
+}
+
+func TestDo_MarkTemporaryAfter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxCount: 3,
+		}
+		myErr := errors.New("some error")
+		start := time.Now()
+		err := policy.Do(t.Context(), func() error {
+			return MarkTemporaryAfter(myErr, 10*time.Second)
+		})
+		if err != myErr {
+			t.Errorf("want %v, got %v", myErr, err)
+		}
+		if d := time.Since(start); d != 20*time.Second {
+			t.Errorf("want 20s, got %s", d)
+		}
+	})
+}
+
+func TestDoValue_RetryAfter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay:      time.Second,
+			MaxDelay:      time.Minute,
+			MaxRetryAfter: 30 * time.Second,
+		}
+
+		delays := []time.Duration{
+			5 * time.Second,
+			time.Hour, // clamped by MaxRetryAfter
+			0,         // use the back off
+		}
+		count := 0
+		start := time.Now()
+		v, err := DoValue(t.Context(), policy, func() (int, error) {
+			count++
+			if count <= len(delays) {
+				return 0, fmt.Errorf("wrapped: %w", &throttlingError{delays[count-1]})
+			}
+			return 42, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != 42 {
+			t.Errorf("want %d, got %d", 42, v)
+		}
+
+		// 5s + 30s + 4s
+		if d := time.Since(start); d != 39*time.Second {
+			t.Errorf("want 39s, got %s", d)
+		}
+	})
+}
+
+func TestDo_ClassifierRetryAfter(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		policy := &Policy{
+			MinDelay: time.Second,
+			MaxCount: 3,
+			Classifier: func(err error) Decision {
+				return RetryAfter(5 * time.Second)
+			},
+		}
+		start := time.Now()
+		policy.Do(t.Context(), func() error {
+			return errors.New("some error")
+		})
+		if d := time.Since(start); d != 10*time.Second {
+			t.Errorf("want 10s, got %s", d)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+// Package retrytest provides utilities for testing code that uses the retry package.
+package retrytest
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shogo82148/go-retry/v2"
+	"github.com/shogo82148/go-retry/v2/fakeclock"
+)
+
+// Operation is a scripted fake operation.
+// It is safe for concurrent use.
+type Operation struct {
+	mu     sync.Mutex
+	calls  int
+	script func(call int) error
+}
+
+// FailN returns an [Operation] that fails with err n times and then succeeds.
+func FailN(n int, err error) *Operation {
+	return &Operation{
+		script: func(call int) error {
+			if call <= n {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+// FailSequence returns an [Operation] that returns errs in order and then succeeds.
+// A nil error in errs means that the call succeeds.
+func FailSequence(errs ...error) *Operation {
+	errs = slices.Clone(errs)
+	return &Operation{
+		script: func(call int) error {
+			if call <= len(errs) {
+				return errs[call-1]
+			}
+			return nil
+		},
+	}
+}
+
+// FailUntil returns an [Operation] that fails with err until the time of clock reaches t.
+// If clock is nil, the system clock is used.
+func FailUntil(clock retry.Clock, t time.Time, err error) *Operation {
+	return &Operation{
+		script: func(call int) error {
+			var now time.Time
+			if clock != nil {
+				now = clock.Now()
+			} else {
+				now = time.Now()
+			}
+			if now.Before(t) {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+// Call calls the operation.
+// It can be passed to [retry.Policy.Do].
+func (o *Operation) Call() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.calls++
+	return o.script(o.calls)
+}
+
+// CallContext calls the operation.
+// It can be passed to [retry.Policy.DoContext].
+func (o *Operation) CallContext(ctx context.Context) error {
+	return o.Call()
+}
+
+// Calls returns the number of calls.
+func (o *Operation) Calls() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.calls
+}
+
+// Recorder records retry events.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	retries []retry.Event
+	giveUps []retry.Event
+	success []retry.Event
+}
+
+// Hooks returns the hooks that record events to r.
+func (r *Recorder) Hooks() *retry.Hooks {
+	return &retry.Hooks{
+		OnRetry: func(e retry.Event) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.retries = append(r.retries, e)
+		},
+		OnGiveUp: func(e retry.Event) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.giveUps = append(r.giveUps, e)
+		},
+		OnSuccess: func(e retry.Event) {
+			r.mu.Lock()
+			defer r.mu.Unlock()
+			r.success = append(r.success, e)
+		},
+	}
+}
+
+// Retries returns the recorded OnRetry events.
+func (r *Recorder) Retries() []retry.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.retries)
+}
+
+// GiveUps returns the recorded OnGiveUp events.
+func (r *Recorder) GiveUps() []retry.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.giveUps)
+}
+
+// Successes returns the recorded OnSuccess events.
+func (r *Recorder) Successes() []retry.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.success)
+}
+
+// Delays returns the delays of the recorded OnRetry events.
+func (r *Recorder) Delays() []time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delays := make([]time.Duration, 0, len(r.retries))
+	for _, e := range r.retries {
+		delays = append(delays, e.Delay)
+	}
+	return delays
+}
+
+// Delays runs the retry loop of policy without real sleeping, and returns the delays between attempts.
+// The loop stops when n delays are recorded or the retrier gives up.
+// The Clock and Hooks of policy are replaced by a fake clock and a [Recorder].
+// The Budget, Breaker, Throttler and Metrics of policy are ignored,
+// because they may be shared with the real retriers.
+func Delays(policy *retry.Policy, n int) []time.Duration {
+	clock := fakeclock.New(time.Now())
+	clock.AutoAdvance(true)
+
+	var rec Recorder
+	p := *policy
+	p.Clock = clock
+	p.Hooks = rec.Hooks()
+	p.Budget = nil
+	p.Breaker = nil
+	p.Throttler = nil
+	p.Metrics = nil
+
+	retrier := p.Start(context.Background())
+	for i := 0; i <= n && retrier.Continue(); i++ {
+	}
+	return rec.Delays()
+}
+
+// AssertDelays asserts that the delays between attempts of policy are want.
+// It runs the retry loop under a fake clock, so it doesn't sleep actually.
+func AssertDelays(t testing.TB, policy *retry.Policy, want []time.Duration) {
+	t.Helper()
+	got := Delays(policy, len(want))
+	if !slices.Equal(got, want) {
+		t.Errorf("unexpected delays: want %v, got %v", want, got)
+	}
+}
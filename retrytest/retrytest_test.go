@@ -0,0 +1,110 @@
+package retrytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shogo82148/go-retry/v2"
+	"github.com/shogo82148/go-retry/v2/fakeclock"
+)
+
+func TestFailN(t *testing.T) {
+	myErr := errors.New("some error")
+	op := FailN(2, myErr)
+	policy := &retry.Policy{MaxCount: 10}
+	if err := policy.Do(context.Background(), op.Call); err != nil {
+		t.Fatal(err)
+	}
+	if op.Calls() != 3 {
+		t.Errorf("want %d, got %d", 3, op.Calls())
+	}
+}
+
+func TestFailSequence(t *testing.T) {
+	err1 := errors.New("error 1")
+	err2 := errors.New("error 2")
+	op := FailSequence(err1, retry.MarkPermanent(err2))
+	policy := &retry.Policy{MaxCount: 10}
+	err := policy.DoContext(context.Background(), op.CallContext)
+	if !errors.Is(err, err2) {
+		t.Errorf("want %v, got %v", err2, err)
+	}
+	if op.Calls() != 2 {
+		t.Errorf("want %d, got %d", 2, op.Calls())
+	}
+}
+
+func TestFailUntil(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := fakeclock.New(start)
+	clock.AutoAdvance(true)
+
+	var rec Recorder
+	op := FailUntil(clock, start.Add(10*time.Second), errors.New("some error"))
+	policy := &retry.Policy{
+		MinDelay: time.Second,
+		MaxDelay: time.Minute,
+		Clock:    clock,
+		Hooks:    rec.Hooks(),
+	}
+	if err := policy.Do(context.Background(), op.Call); err != nil {
+		t.Fatal(err)
+	}
+
+	// 1s + 2s + 4s + 8s = 15s >= 10s
+	if op.Calls() != 5 {
+		t.Errorf("want %d, got %d", 5, op.Calls())
+	}
+	if n := len(rec.Successes()); n != 1 {
+		t.Errorf("want 1 success, got %d", n)
+	}
+	if n := len(rec.GiveUps()); n != 0 {
+		t.Errorf("want no give ups, got %d", n)
+	}
+}
+
+func TestAssertDelays(t *testing.T) {
+	policy := &retry.Policy{
+		MinDelay: time.Second,
+		MaxDelay: 10 * time.Second,
+	}
+	AssertDelays(t, policy, []time.Duration{
+		time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second,
+	})
+
+	// the policy is not modified.
+	if policy.Clock != nil || policy.Hooks != nil {
+		t.Error("the policy is modified")
+	}
+}
+
+func TestDelays_SharedLimits(t *testing.T) {
+	// the budget allows no retries, and it is shared with the real retriers.
+	budget := retry.NewBudget(0, 0)
+	policy := &retry.Policy{
+		MinDelay: time.Second,
+		MaxDelay: 10 * time.Second,
+		Budget:   budget,
+	}
+	AssertDelays(t, policy, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second})
+
+	// the policy is not modified.
+	if policy.Budget != budget {
+		t.Error("the policy is modified")
+	}
+}
+
+func TestDelays_MaxCount(t *testing.T) {
+	policy := &retry.Policy{
+		MinDelay: time.Second,
+		MaxDelay: time.Minute,
+		MaxCount: 3,
+	}
+	got := Delays(policy, 10)
+	want := []time.Duration{time.Second, 2 * time.Second}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// ErrThrottled is returned by [Policy.Do] and [DoValue] when [Policy.Throttler] rejects an attempt.
+var ErrThrottled = errors.New("retry: request is throttled")
+
+// throttleWindow is the time window of throttlers.
+const throttleWindow = 2 * time.Minute
+
+// Throttler rejects requests locally while the backend rejects many requests.
+// It is the adaptive throttling described in the Site Reliability Engineering book.
+//
+// Within the last 2 minutes, the requests are rejected with the probability of
+//
+//	max(0, (requests - k*accepts) / (requests + 1))
+//
+// where requests is the number of requests including the rejected ones,
+// and accepts is the number of requests accepted by the backend.
+// It is safe for concurrent use, and it can be shared by many policies targeting the same backend.
+type Throttler struct {
+	mu     sync.Mutex
+	k      float64
+	window *window
+	rand   func() float64
+}
+
+// NewThrottler returns a new [Throttler].
+// k is the multiplier of accepts, typically 2.
+// Smaller k rejects requests more aggressively.
+func NewThrottler(k float64) *Throttler {
+	return &Throttler{
+		k:      k,
+		window: newWindow(throttleWindow, 12),
+		rand:   rand.Float64,
+	}
+}
+
+// Allow reports whether a request is allowed, and records the request.
+// [Retrier.Continue] calls it before sleeping for each attempt.
+func (t *Throttler) Allow() bool {
+	return t.allow(time.Now())
+}
+
+func (t *Throttler) allow(now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	requests, accepts := t.window.sum(now)
+	t.window.add(now, 0, 1)
+	p := (float64(requests) - t.k*float64(accepts)) / float64(requests+1)
+	return p <= 0 || t.rand() >= p
+}
+
+// Accept records a request accepted by the backend.
+// [Policy.Do], [DoValue] and their variants call it when an attempt succeeds or fails with a permanent error.
+// Call it manually when a policy with a throttler is used by [Retrier.Continue].
+func (t *Throttler) Accept() {
+	t.accept(time.Now())
+}
+
+func (t *Throttler) accept(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.window.add(now, 1, 1)
+}
+
+// throttle reports whether [Policy.Throttler] allows the next attempt.
+func (r *Retrier) throttle() bool {
+	t := r.policy.Throttler
+	return t == nil || t.allow(r.clock.Now())
+}
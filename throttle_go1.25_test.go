@@ -0,0 +1,89 @@
+//go:build go1.25
+// +build go1.25
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestThrottler(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		th := NewThrottler(2)
+		th.rand = func() float64 { return 0.5 }
+
+		// the backend accepts all requests.
+		for i := range 10 {
+			if !th.Allow() {
+				t.Fatalf("#%d: want to be allowed, but not", i)
+			}
+			th.Accept()
+		}
+
+		// the backend rejects all requests.
+		// the requests are rejected when (10+i - 2*10) / (10+i+1) > 0.5.
+		for i := range 32 {
+			if !th.Allow() {
+				t.Fatalf("#%d: want to be allowed, but not", i)
+			}
+		}
+		if th.Allow() {
+			t.Fatal("want to be rejected, but allowed")
+		}
+
+		// the old requests are expired.
+		time.Sleep(2 * time.Minute)
+		if !th.Allow() {
+			t.Fatal("want to be allowed, but not")
+		}
+	})
+}
+
+func TestDo_Throttler(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		th := NewThrottler(2)
+		th.rand = func() float64 { return 0 }
+		policy := &Policy{
+			MinDelay:  time.Second,
+			MaxCount:  10,
+			Throttler: th,
+		}
+
+		// the second attempt is rejected without sleeping, because the first one is not accepted.
+		var count int
+		start := time.Now()
+		err := policy.Do(context.Background(), func() error {
+			count++
+			return io.ErrUnexpectedEOF
+		})
+		if !errors.Is(err, ErrThrottled) {
+			t.Errorf("want %v to be wrapped in %v", ErrThrottled, err)
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("want %v to be wrapped in %v", io.ErrUnexpectedEOF, err)
+		}
+		if count != 1 {
+			t.Errorf("want %d, got %d", 1, count)
+		}
+		if d := time.Since(start); d != 0 {
+			t.Errorf("want %s, got %s", time.Duration(0), d)
+		}
+
+		// the permanent error is accepted by the backend.
+		time.Sleep(2 * time.Minute)
+		err = policy.Do(context.Background(), func() error {
+			return MarkPermanent(io.EOF)
+		})
+		if err != io.EOF {
+			t.Errorf("want %v, got %v", io.EOF, err)
+		}
+		if !th.Allow() {
+			t.Error("want to be allowed, but not")
+		}
+	})
+}
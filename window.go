@@ -0,0 +1,54 @@
+package retry
+
+import "time"
+
+// window is a sliding window of two counters.
+// It is divided into buckets, and the buckets older than the window are discarded.
+type window struct {
+	buckets []bucket
+	width   time.Duration // the width of a bucket
+	head    int           // the index of the newest bucket
+	start   time.Time     // the start time of the newest bucket
+}
+
+type bucket [2]int64
+
+func newWindow(size time.Duration, n int) *window {
+	return &window{
+		buckets: make([]bucket, n),
+		width:   size / time.Duration(n),
+	}
+}
+
+// advance discards the buckets older than the window.
+func (w *window) advance(now time.Time) {
+	if w.start.IsZero() {
+		w.start = now.Truncate(w.width)
+		return
+	}
+	n := int(now.Sub(w.start) / w.width)
+	if n <= 0 {
+		return
+	}
+	for i := 0; i < n && i < len(w.buckets); i++ {
+		w.head = (w.head + 1) % len(w.buckets)
+		w.buckets[w.head] = bucket{}
+	}
+	w.start = w.start.Add(time.Duration(n) * w.width)
+}
+
+// add adds delta to the i-th counter of the newest bucket.
+func (w *window) add(now time.Time, i int, delta int64) {
+	w.advance(now)
+	w.buckets[w.head][i] += delta
+}
+
+// sum returns the sums of the counters in the window.
+func (w *window) sum(now time.Time) (a, b int64) {
+	w.advance(now)
+	for _, bucket := range w.buckets {
+		a += bucket[0]
+		b += bucket[1]
+	}
+	return
+}